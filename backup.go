@@ -0,0 +1,507 @@
+package typesense
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/baderkha/typesense/pkg/conditional"
+	http2 "github.com/baderkha/typesense/pkg/http"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// backupFormatVersion : version of the backup layout , stored in the manifest
+	backupFormatVersion = 1
+
+	// BackupManifestFile : describes the backup , written last so a backup without it is incomplete
+	BackupManifestFile = "manifest.json"
+	// BackupSchemaFile : the collection schema
+	BackupSchemaFile = "schema.json"
+	// BackupDocumentsFile : the exported documents (jsonl)
+	BackupDocumentsFile = "documents.jsonl"
+	// BackupDocumentsGzipFile : the exported documents when the backup is gzipped
+	BackupDocumentsGzipFile = "documents.jsonl.gz"
+	// BackupAliasesFile : the aliases pointing at the collection
+	BackupAliasesFile = "aliases.json"
+	// BackupSynonymsFile : the synonyms of the collection
+	BackupSynonymsFile = "synonyms.json"
+	// BackupOverridesFile : the overrides (curation rules) of the collection
+	BackupOverridesFile = "overrides.json"
+)
+
+// IBackupClient : backs up collections to / restores them from a file system (the package one , see OverrideFS , or WithFS)
+type IBackupClient interface {
+	// Backup : dumps the collection (or the collection behind the alias) into dir :
+	//
+	//		manifest.json , schema.json , documents.jsonl(.gz) , aliases.json , synonyms.json , overrides.json
+	//
+	// Example :
+	//			backup := typesense.NewBackupClient("<api_key>", "<http_server_url>", false).WithFS(afero.NewOsFs())
+	//			manifest, err := backup.Backup("products", "/backups/products/2022-10-10", &typesense.BackupOptions{
+	//				Gzip:      true,
+	//				Synonyms:  true,
+	//				Overrides: true,
+	//			})
+	//
+	Backup(name string, dir string, opts *BackupOptions) (*BackupManifest, error)
+	// Restore : recreates the backup in dir into a new versioned collection (<name>_<date>_<version>) and returns its name .
+	// the new collection is deleted if the documents could not all be imported
+	//
+	// Example :
+	//			// products -> products_2022-10-11_<SomeHash>
+	//			colName, err := backup.Restore("/backups/products/2022-10-10", &typesense.RestoreOptions{Alias: true})
+	//
+	Restore(dir string, opts *RestoreOptions) (string, error)
+	// ReadManifest : reads the manifest of the backup in dir
+	ReadManifest(dir string) (*BackupManifest, error)
+	// WithFS : Override the file system for local operations and not globally
+	WithFS(fs afero.Fs) IBackupClient
+}
+
+// BackupOptions : what goes in a backup besides the schema , documents and aliases
+type BackupOptions struct {
+	// Gzip : gzip the documents
+	Gzip bool
+	// Synonyms : back up the synonyms of the collection
+	Synonyms bool
+	// Overrides : back up the overrides (curation rules) of the collection
+	Overrides bool
+}
+
+// RestoreOptions : how a backup is restored
+type RestoreOptions struct {
+	// Name : base name of the restored collection (defaults to the name the backup was taken with)
+	Name string
+	// Alias : point the aliases at the restored collection . when Name is set only that alias is pointed ,
+	// otherwise the aliases of the backup (or the backup name if it had none)
+	Alias bool
+	// Import : how the documents are streamed back (chunking , concurrency , progress)
+	Import *ImportStreamOptions
+}
+
+// BackupManifest : describes a backup
+type BackupManifest struct {
+	Version int `json:"version"`
+	// Name : the alias / collection name the backup was taken with
+	Name string `json:"name"`
+	// Collection : the collection that was backed up
+	Collection string  `json:"collection"`
+	Aliases    []Alias `json:"aliases"`
+	Documents  int64   `json:"documents"`
+	Gzip       bool    `json:"gzip"`
+	Synonyms   bool    `json:"synonyms"`
+	Overrides  bool    `json:"overrides"`
+	CreatedAt  int64   `json:"created_at"` // unix milliseconds
+	AppVersion string  `json:"app_version,omitempty"`
+}
+
+// NewBackupClient : create a new backup client which dumps / restores collections on an afero file system
+func NewBackupClient(apiKey string, host string, logging bool) IBackupClient {
+	base := newBaseClient[any](apiKey, host, logging)
+	return &BackupClient{
+		baseClient: base,
+	}
+}
+
+// BackupClient : backs up collections to / restores them from a file system
+type BackupClient struct {
+	*baseClient[any]
+	fs afero.Fs
+}
+
+func (b *BackupClient) getFS() afero.Fs {
+	return conditional.Ternary(b.fs != nil, b.fs, fs)
+}
+
+// WithFS : Override the file system for local operations and not globally
+func (b *BackupClient) WithFS(fs afero.Fs) IBackupClient {
+	var newBackup BackupClient
+	newBackup = *b
+	newBackup.fs = fs
+	return &newBackup
+}
+
+// Backup : dumps the collection (or the collection behind the alias) into dir
+func (b *BackupClient) Backup(name string, dir string, opts *BackupOptions) (*BackupManifest, error) {
+	if opts == nil {
+		opts = &BackupOptions{}
+	}
+	colName := name
+	if exists, alias := b.GetAlias(name); exists {
+		colName = alias.CollectionName
+	}
+	schema, err := b.collectionSchema(colName)
+	if err != nil {
+		return nil, err
+	} else if schema == nil {
+		return nil, fmt.Errorf("Typesense : cannot back up %s , collection %s does not exist", name, colName)
+	}
+
+	backupFS := b.getFS()
+	err = backupFS.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, errors.Wrap(err, typesenseErrPrefix)
+	}
+	manifest := BackupManifest{
+		Version:    backupFormatVersion,
+		Name:       name,
+		Collection: colName,
+		Gzip:       opts.Gzip,
+		Synonyms:   opts.Synonyms,
+		Overrides:  opts.Overrides,
+		CreatedAt:  time.Now().UnixMilli(),
+		AppVersion: migrationAppVersion,
+	}
+
+	err = writeJSONFile(backupFS, filepath.Join(dir, BackupSchemaFile), schema)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.Aliases, err = b.collectionAliases(colName)
+	if err != nil {
+		return nil, err
+	}
+	err = writeJSONFile(backupFS, filepath.Join(dir, BackupAliasesFile), manifest.Aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.Documents, err = b.backupDocuments(backupFS, dir, colName, opts.Gzip)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Synonyms {
+		err = b.backupList(backupFS, filepath.Join(dir, BackupSynonymsFile), colName, "synonyms")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.Overrides {
+		err = b.backupList(backupFS, filepath.Join(dir, BackupOverridesFile), colName, "overrides")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = writeJSONFile(backupFS, filepath.Join(dir, BackupManifestFile), manifest)
+	if err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// ReadManifest : reads the manifest of the backup in dir
+func (b *BackupClient) ReadManifest(dir string) (*BackupManifest, error) {
+	var manifest BackupManifest
+	err := readJSONFile(b.getFS(), filepath.Join(dir, BackupManifestFile), &manifest)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Version > backupFormatVersion {
+		return nil, fmt.Errorf("Typesense : backup format version %d is newer than this client supports (%d)", manifest.Version, backupFormatVersion)
+	}
+	return &manifest, nil
+}
+
+// Restore : recreates the backup in dir into a new versioned collection and returns its name
+func (b *BackupClient) Restore(dir string, opts *RestoreOptions) (string, error) {
+	if opts == nil {
+		opts = &RestoreOptions{}
+	}
+	backupFS := b.getFS()
+	manifest, err := b.ReadManifest(dir)
+	if err != nil {
+		return "", err
+	}
+	// the schema is restored as it was backed up , so settings the Collection struct doesn't have are kept
+	var schema map[string]json.RawMessage
+	err = readJSONFile(backupFS, filepath.Join(dir, BackupSchemaFile), &schema)
+	if err != nil {
+		return "", err
+	}
+
+	name := conditional.Ternary(opts.Name != "", opts.Name, manifest.Name)
+	migration := Migration[any]{baseClient: b.baseClient}
+	colName := b.VersionCollectionName(name)
+	err = b.createCollectionFromSchema(colName, schema)
+	if err != nil {
+		return "", err
+	}
+
+	err = b.restoreData(backupFS, dir, manifest, colName, opts.Import)
+	if err != nil {
+		// don't leave a half restored collection behind
+		_ = migration.DeleteCollection(colName, nil)
+		return "", err
+	}
+
+	if opts.Alias {
+		aliasNames := []string{name}
+		if opts.Name == "" && len(manifest.Aliases) > 0 {
+			aliasNames = aliasNames[:0]
+			for _, alias := range manifest.Aliases {
+				aliasNames = append(aliasNames, alias.Name)
+			}
+		}
+		for _, aliasName := range aliasNames {
+			err = migration.AliasCollection(&Alias{Name: aliasName, CollectionName: colName})
+			if err != nil {
+				return colName, errors.Wrap(err, "Typesense : backup restored but could not be aliased")
+			}
+			b.forgetAlias(aliasName)
+		}
+	}
+	return colName, nil
+}
+
+// collectionSchema : the collection as typesense returns it (nil if it does not exist) , kept raw so the backup has
+// every setting of the collection (token separators , symbols to index , field options ..etc)
+func (b *BackupClient) collectionSchema(colName string) (json.RawMessage, error) {
+	res, err := b.Req().
+		Get(fmt.Sprintf("/collections/%s", colName))
+	if err != nil {
+		return nil, err
+	} else if res.StatusCode() == http.StatusNotFound {
+		return nil, nil
+	} else if !http2.StatusIsSuccess(res.StatusCode()) {
+		return nil, typesenseToError(res.Body(), res.StatusCode())
+	}
+	return json.RawMessage(res.Body()), nil
+}
+
+// createCollectionFromSchema : creates the collection from a backed up schema under a new name
+func (b *BackupClient) createCollectionFromSchema(colName string, schema map[string]json.RawMessage) error {
+	// set by typesense , can't be sent when creating
+	delete(schema, "created_at")
+	delete(schema, "num_documents")
+	name, err := json.Marshal(colName)
+	if err != nil {
+		return errors.Wrap(err, typesenseErrPrefix)
+	}
+	schema["name"] = name
+
+	res, err := b.Req().
+		SetBody(schema).
+		Post("/collections")
+	if err != nil {
+		return err
+	} else if !http2.StatusIsSuccess(res.StatusCode()) {
+		return typesenseToError(res.Body(), res.StatusCode())
+	}
+	return nil
+}
+
+// restoreData : imports the documents , synonyms and overrides of the backup into the collection
+func (b *BackupClient) restoreData(backupFS afero.Fs, dir string, manifest *BackupManifest, colName string, importOpts *ImportStreamOptions) error {
+	docsFile := conditional.Ternary(manifest.Gzip, BackupDocumentsGzipFile, BackupDocumentsFile)
+	file, err := backupFS.Open(filepath.Join(dir, docsFile))
+	if err != nil {
+		return errors.Wrap(err, typesenseErrPrefix)
+	}
+	defer file.Close()
+	var docs io.Reader = file
+	if manifest.Gzip {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return errors.Wrap(err, typesenseErrPrefix)
+		}
+		defer gzipReader.Close()
+		docs = gzipReader
+	}
+
+	docClient := &DocumentClient[any]{
+		baseClient: &baseClient[any]{
+			r:            b.r,
+			aliasCache:   make(map[string]string),
+			isNotAliased: true,
+			colName:      colName,
+		},
+	}
+	_, err = docClient.ImportStream(docs, DocumentActionCreate, importOpts)
+	if err != nil {
+		return err
+	}
+
+	if manifest.Synonyms {
+		err = b.restoreList(backupFS, filepath.Join(dir, BackupSynonymsFile), colName, "synonyms")
+		if err != nil {
+			return err
+		}
+	}
+	if manifest.Overrides {
+		err = b.restoreList(backupFS, filepath.Join(dir, BackupOverridesFile), colName, "overrides")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectionAliases : the aliases pointing at the collection
+func (b *BackupClient) collectionAliases(colName string) ([]Alias, error) {
+	var aliasesRes struct {
+		Aliases []Alias `json:"aliases"`
+	}
+	res, err := b.Req().
+		SetResult(&aliasesRes).
+		Get("/aliases")
+	if err != nil {
+		return nil, err
+	} else if !http2.StatusIsSuccess(res.StatusCode()) {
+		return nil, typesenseToError(res.Body(), res.StatusCode())
+	}
+	aliases := []Alias{}
+	for _, alias := range aliasesRes.Aliases {
+		if alias.CollectionName == colName {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases, nil
+}
+
+// backupDocuments : streams the export of the collection to the documents file , returns the number of documents
+func (b *BackupClient) backupDocuments(backupFS afero.Fs, dir string, colName string, isGzip bool) (int64, error) {
+	export, err := b.exportStream(colName, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer export.Close()
+
+	docsFile := conditional.Ternary(isGzip, BackupDocumentsGzipFile, BackupDocumentsFile)
+	file, err := backupFS.Create(filepath.Join(dir, docsFile))
+	if err != nil {
+		return 0, errors.Wrap(err, typesenseErrPrefix)
+	}
+	documents, err := writeDocuments(file, export, isGzip)
+	// remote file systems (ie s3) upload on close , a failed close means the documents are not backed up
+	closeErr := file.Close()
+	if err != nil {
+		return 0, err
+	}
+	if closeErr != nil {
+		return 0, errors.Wrap(closeErr, typesenseErrPrefix)
+	}
+	return documents, nil
+}
+
+// writeDocuments : copies the jsonl export to the file (gzipped or not) , returns the number of documents
+func writeDocuments(file io.Writer, export io.Reader, isGzip bool) (int64, error) {
+	var out io.Writer = file
+	var gzipWriter *gzip.Writer
+	if isGzip {
+		gzipWriter = gzip.NewWriter(file)
+		out = gzipWriter
+	}
+	counter := &lineCounter{}
+	_, err := io.Copy(io.MultiWriter(out, counter), export)
+	if err != nil {
+		return 0, errors.Wrap(err, typesenseErrPrefix)
+	}
+	if gzipWriter != nil {
+		err = gzipWriter.Close()
+		if err != nil {
+			return 0, errors.Wrap(err, typesenseErrPrefix)
+		}
+	}
+	return counter.lines(), nil
+}
+
+// backupList : writes the synonyms / overrides of the collection to a file
+func (b *BackupClient) backupList(backupFS afero.Fs, path string, colName string, kind string) error {
+	var listRes map[string][]map[string]interface{}
+	res, err := b.Req().
+		SetResult(&listRes).
+		Get(fmt.Sprintf("/collections/%s/%s", colName, kind))
+	if err != nil {
+		return err
+	} else if !http2.StatusIsSuccess(res.StatusCode()) {
+		return typesenseToError(res.Body(), res.StatusCode())
+	}
+	list := listRes[kind]
+	if list == nil {
+		list = []map[string]interface{}{}
+	}
+	return writeJSONFile(backupFS, path, list)
+}
+
+// restoreList : upserts the synonyms / overrides of a file into the collection
+func (b *BackupClient) restoreList(backupFS afero.Fs, path string, colName string, kind string) error {
+	var list []map[string]interface{}
+	err := readJSONFile(backupFS, path, &list)
+	if err != nil {
+		return err
+	}
+	for _, item := range list {
+		id := fmt.Sprint(item["id"])
+		delete(item, "id")
+		res, err := b.Req().
+			SetBody(item).
+			Put(fmt.Sprintf("/collections/%s/%s/%s", colName, kind, id))
+		if err != nil {
+			return err
+		} else if !http2.StatusIsSuccess(res.StatusCode()) {
+			return typesenseToError(res.Body(), res.StatusCode())
+		}
+	}
+	return nil
+}
+
+// writeJSONFile : afero.WriteFile returns the close error too (see backupDocuments)
+func writeJSONFile(backupFS afero.Fs, path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, typesenseErrPrefix)
+	}
+	err = afero.WriteFile(backupFS, path, b, 0644)
+	if err != nil {
+		return errors.Wrap(err, typesenseErrPrefix)
+	}
+	return nil
+}
+
+func readJSONFile(backupFS afero.Fs, path string, v interface{}) error {
+	b, err := afero.ReadFile(backupFS, path)
+	if err != nil {
+		return errors.Wrap(err, typesenseErrPrefix)
+	}
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Typesense : could not read %s", path))
+	}
+	return nil
+}
+
+// lineCounter : counts the jsonl lines written through it (the last line may not end with a new line)
+type lineCounter struct {
+	count   int64
+	pending bool
+}
+
+func (c *lineCounter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b == '\n' {
+			if c.pending {
+				c.count++
+			}
+			c.pending = false
+		} else {
+			c.pending = true
+		}
+	}
+	return len(p), nil
+}
+
+func (c *lineCounter) lines() int64 {
+	if c.pending {
+		return c.count + 1
+	}
+	return c.count
+}
@@ -0,0 +1,331 @@
+package typesense
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// backupTestServer : a typesense with the products alias -> products_v1 collection , records what restores create
+type backupTestServer struct {
+	*httptest.Server
+	mu        sync.Mutex
+	created   []map[string]interface{}
+	deleted   []string
+	imported  map[string][]string
+	synonyms  map[string]map[string]interface{}
+	aliases   map[string]string
+	rejectIDs map[string]bool
+}
+
+// backupTestSchema : the collection as typesense returns it , with settings the Collection struct doesn't have
+const backupTestSchema = `{
+  "created_at": 1665360000,
+  "default_sorting_field": "",
+  "enable_nested_fields": false,
+  "fields": [
+    {"facet": false, "index": true, "infix": true, "locale": "fr", "name": "name", "optional": false, "sort": false, "stem": true, "store": true, "type": "string"},
+    {"facet": false, "index": true, "name": "price", "optional": true, "range_index": true, "sort": true, "type": "float"},
+    {"facet": false, "index": true, "name": "brand_id", "optional": true, "reference": "brands.id", "sort": false, "type": "string"}
+  ],
+  "name": "products_v1",
+  "num_documents": 3,
+  "symbols_to_index": ["+"],
+  "token_separators": ["-", "/"]
+}`
+
+const backupTestExport = `{"id":"1","name":"shoe"}
+{"id":"2","name":"sock"}
+{"id":"3","name":"hat"}`
+
+func newBackupTestServer(t *testing.T) *backupTestServer {
+	s := &backupTestServer{
+		imported:  make(map[string][]string),
+		synonyms:  make(map[string]map[string]interface{}),
+		aliases:   make(map[string]string),
+		rejectIDs: make(map[string]bool),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		switch {
+		case r.Method == http.MethodGet && path == "/aliases/products":
+			_, _ = w.Write([]byte(`{"name":"products","collection_name":"products_v1"}`))
+		case r.Method == http.MethodGet && path == "/aliases":
+			_, _ = w.Write([]byte(`{"aliases":[` +
+				`{"name":"products","collection_name":"products_v1"},` +
+				`{"name":"catalog","collection_name":"products_v1"},` +
+				`{"name":"users","collection_name":"users_v1"}]}`))
+		case r.Method == http.MethodGet && path == "/collections/products_v1":
+			_, _ = w.Write([]byte(backupTestSchema))
+		case r.Method == http.MethodGet && path == "/collections/products_v1/documents/export":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(backupTestExport))
+		case r.Method == http.MethodGet && path == "/collections/products_v1/synonyms":
+			_, _ = w.Write([]byte(`{"synonyms":[{"id":"footwear","synonyms":["shoe","sneaker"]}]}`))
+		case r.Method == http.MethodGet && path == "/collections/products_v1/overrides":
+			_, _ = w.Write([]byte(`{"overrides":[]}`))
+		case r.Method == http.MethodPost && path == "/collections":
+			var col map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&col)
+			if _, ok := col["created_at"]; ok {
+				t.Errorf("created collection with read only fields %+v", col)
+			} else if _, ok := col["num_documents"]; ok {
+				t.Errorf("created collection with read only fields %+v", col)
+			}
+			s.created = append(s.created, col)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(col)
+		case r.Method == http.MethodDelete && strings.HasPrefix(path, "/collections/"):
+			s.deleted = append(s.deleted, strings.TrimPrefix(path, "/collections/"))
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/documents/import"):
+			colName := strings.Split(path, "/")[2]
+			var results []string
+			scanner := bufio.NewScanner(r.Body)
+			for scanner.Scan() {
+				var doc struct {
+					ID string `json:"id"`
+				}
+				_ = json.Unmarshal(scanner.Bytes(), &doc)
+				if s.rejectIDs[doc.ID] {
+					results = append(results, fmt.Sprintf(`{"success":false,"error":"rejected","document":%q}`, scanner.Text()))
+					continue
+				}
+				s.imported[colName] = append(s.imported[colName], scanner.Text())
+				results = append(results, `{"success":true}`)
+			}
+			_, _ = w.Write([]byte(strings.Join(results, "\n")))
+		case r.Method == http.MethodPut && strings.Contains(path, "/synonyms/"):
+			var synonym map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&synonym)
+			s.synonyms[path] = synonym
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodPut && strings.HasPrefix(path, "/aliases/"):
+			var alias Alias
+			_ = json.NewDecoder(r.Body).Decode(&alias)
+			s.aliases[strings.TrimPrefix(path, "/aliases/")] = alias.CollectionName
+			_ = json.NewEncoder(w).Encode(alias)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestBackupRestore(t *testing.T) {
+	for _, isGzip := range []bool{false, true} {
+		t.Run(fmt.Sprintf("gzip %v", isGzip), func(t *testing.T) {
+			server := newBackupTestServer(t)
+			backupFS := afero.NewMemMapFs()
+			client := NewBackupClient("key", server.URL, false).WithFS(backupFS)
+
+			manifest, err := client.Backup("products", "/backups/products", &BackupOptions{Gzip: isGzip, Synonyms: true, Overrides: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantAliases := []Alias{{Name: "products", CollectionName: "products_v1"}, {Name: "catalog", CollectionName: "products_v1"}}
+			if manifest.Name != "products" || manifest.Collection != "products_v1" || manifest.Documents != 3 ||
+				manifest.Gzip != isGzip || !manifest.Synonyms || !manifest.Overrides ||
+				manifest.Version != backupFormatVersion || !reflect.DeepEqual(manifest.Aliases, wantAliases) {
+				t.Fatalf("manifest = %+v", manifest)
+			}
+
+			read, err := client.ReadManifest("/backups/products")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(read, manifest) {
+				t.Fatalf("read manifest = %+v , want %+v", read, manifest)
+			}
+			for _, file := range []string{BackupSchemaFile, BackupAliasesFile, BackupSynonymsFile, BackupOverridesFile} {
+				if exists, _ := afero.Exists(backupFS, filepath.Join("/backups/products", file)); !exists {
+					t.Fatalf("%s was not written", file)
+				}
+			}
+			docsFile := BackupDocumentsFile
+			if isGzip {
+				docsFile = BackupDocumentsGzipFile
+			}
+			raw, err := afero.ReadFile(backupFS, filepath.Join("/backups/products", docsFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if isGzip == (string(raw) == backupTestExport) {
+				t.Fatalf("documents file = %q , gzip %v", raw, isGzip)
+			}
+
+			colName, err := client.Restore("/backups/products", &RestoreOptions{Alias: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !isCollectionVersionOf("products", colName) {
+				t.Fatalf("restored into %s , want a products version", colName)
+			}
+			// everything typesense returned is restored but the read only fields
+			var wantSchema map[string]interface{}
+			_ = json.Unmarshal([]byte(backupTestSchema), &wantSchema)
+			delete(wantSchema, "created_at")
+			delete(wantSchema, "num_documents")
+			wantSchema["name"] = colName
+			if len(server.created) != 1 || !reflect.DeepEqual(server.created[0], wantSchema) {
+				t.Fatalf("created = %+v", server.created)
+			}
+			if got := strings.Join(server.imported[colName], "\n"); got != backupTestExport {
+				t.Fatalf("imported = %q", got)
+			}
+			synonym := server.synonyms[fmt.Sprintf("/collections/%s/synonyms/footwear", colName)]
+			if synonym == nil || synonym["id"] != nil {
+				t.Fatalf("synonyms = %+v", server.synonyms)
+			}
+			if !reflect.DeepEqual(server.aliases, map[string]string{"products": colName, "catalog": colName}) {
+				t.Fatalf("aliases = %+v", server.aliases)
+			}
+		})
+	}
+}
+
+func TestRestoreName(t *testing.T) {
+	server := newBackupTestServer(t)
+	client := NewBackupClient("key", server.URL, false).WithFS(afero.NewMemMapFs())
+	_, err := client.Backup("products", "/backup", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	colName, err := client.Restore("/backup", &RestoreOptions{Name: "products_copy", Alias: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isCollectionVersionOf("products_copy", colName) {
+		t.Fatalf("restored into %s", colName)
+	}
+	if !reflect.DeepEqual(server.aliases, map[string]string{"products_copy": colName}) {
+		t.Fatalf("aliases = %+v", server.aliases)
+	}
+}
+
+func TestRestoreDeletesCollectionOnFailedImport(t *testing.T) {
+	server := newBackupTestServer(t)
+	server.rejectIDs["2"] = true
+	client := NewBackupClient("key", server.URL, false).WithFS(afero.NewMemMapFs())
+	_, err := client.Backup("products", "/backup", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Restore("/backup", &RestoreOptions{Alias: true})
+	var importErr *ImportError
+	if !errors.As(err, &importErr) || len(importErr.Failed) != 1 {
+		t.Fatalf("err = %v , want an *ImportError", err)
+	}
+	if len(server.created) != 1 || !reflect.DeepEqual(server.deleted, []string{server.created[0]["name"].(string)}) {
+		t.Fatalf("created = %+v , deleted = %v", server.created, server.deleted)
+	}
+	if len(server.aliases) != 0 {
+		t.Fatalf("aliases = %+v", server.aliases)
+	}
+}
+
+func TestBackupMissingCollection(t *testing.T) {
+	server := newBackupTestServer(t)
+	backupFS := afero.NewMemMapFs()
+	client := NewBackupClient("key", server.URL, false).WithFS(backupFS)
+	_, err := client.Backup("users", "/backup", nil)
+	if err == nil || !strings.Contains(err.Error(), "collection users does not exist") {
+		t.Fatalf("Backup() = %v", err)
+	}
+	if exists, _ := afero.DirExists(backupFS, "/backup"); exists {
+		t.Fatal("nothing should be written")
+	}
+}
+
+// closeErrFS : a file system whose created files fail to close (like a failed upload on a remote file system)
+type closeErrFS struct {
+	afero.Fs
+	failOn string
+}
+
+type closeErrFile struct {
+	afero.File
+}
+
+func (f closeErrFile) Close() error {
+	_ = f.File.Close()
+	return errors.New("upload failed")
+}
+
+func (c closeErrFS) Create(name string) (afero.File, error) {
+	file, err := c.Fs.Create(name)
+	if err != nil || filepath.Base(name) != c.failOn {
+		return file, err
+	}
+	return closeErrFile{file}, nil
+}
+
+func (c closeErrFS) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	file, err := c.Fs.OpenFile(name, flag, perm)
+	if err != nil || filepath.Base(name) != c.failOn {
+		return file, err
+	}
+	return closeErrFile{file}, nil
+}
+
+func TestBackupCloseError(t *testing.T) {
+	for _, failOn := range []string{BackupDocumentsFile, BackupDocumentsGzipFile, BackupSchemaFile} {
+		t.Run(failOn, func(t *testing.T) {
+			server := newBackupTestServer(t)
+			backupFS := closeErrFS{Fs: afero.NewMemMapFs(), failOn: failOn}
+			client := NewBackupClient("key", server.URL, false).WithFS(backupFS)
+
+			_, err := client.Backup("products", "/backup", &BackupOptions{Gzip: failOn == BackupDocumentsGzipFile})
+			if err == nil || !strings.Contains(err.Error(), "upload failed") {
+				t.Fatalf("err = %v , want the close error", err)
+			}
+			if exists, _ := afero.Exists(backupFS, filepath.Join("/backup", BackupManifestFile)); exists {
+				t.Fatal("the manifest should not be written for a failed backup")
+			}
+		})
+	}
+}
+
+func TestLineCounter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   int64
+	}{
+		{name: "empty", want: 0},
+		{name: "trailing new line", writes: []string{"a\nb\n"}, want: 2},
+		{name: "no trailing new line", writes: []string{"a\nb"}, want: 2},
+		{name: "blank lines", writes: []string{"\n\na\n\n"}, want: 1},
+		{name: "split writes", writes: []string{"{\"id\":", "\"1\"}\n{\"id\"", ":\"2\"}"}, want: 2},
+		{name: "new line alone", writes: []string{"a", "\n", "b", "\n"}, want: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			counter := &lineCounter{}
+			for _, write := range test.writes {
+				_, _ = io.WriteString(counter, write)
+			}
+			if counter.lines() != test.want {
+				t.Fatalf("lines() = %d , want %d", counter.lines(), test.want)
+			}
+		})
+	}
+}
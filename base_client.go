@@ -5,15 +5,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"sort"
 	"sync"
 	"time"
 
-	"github.com/baderkha/typesense/pkg/conditional"
+	http2 "github.com/baderkha/typesense/pkg/http"
 	"github.com/baderkha/typesense/pkg/reflection"
 	"github.com/baderkha/typesense/pkg/stringutil"
 	"github.com/go-resty/resty/v2"
 	"github.com/lithammer/shortuuid/v4"
-	"github.com/tkrajina/go-reflector/reflector"
 	"github.com/wlredeye/jsonlines"
 )
 
@@ -27,11 +28,15 @@ type baseClient[T any] struct {
 
 // getCollectionName : gets an underscore name from the struct field
 func (m *baseClient[T]) getCollectionName() string {
+	return modelCollectionName[T]()
+}
+
+// modelCollectionName : gets an underscore name from the struct name
+func modelCollectionName[T any]() string {
 	var mdl T
 	name, _ := reflection.GetTypeName(mdl)
 
 	return stringutil.Underscore(name)
-
 }
 
 func (m *baseClient[T]) Req() *resty.Request {
@@ -45,11 +50,38 @@ func (m *baseClient[T]) VersionCollectionName(colName string) string {
 	return fmt.Sprintf("%s_%s_%s", colName, golangDateTime, hash)
 }
 
+// isCollectionVersionOf : checks if the collection name was produced by VersionCollectionName for the alias
+func isCollectionVersionOf(aliasName string, colName string) bool {
+	versionPattern := regexp.MustCompile(fmt.Sprintf(`^%s_\d{4}-\d{2}-\d{2}_[0-9A-Za-z]+$`, regexp.QuoteMeta(aliasName)))
+	return versionPattern.MatchString(colName)
+}
+
+// isNewerVersion : compares 2 versioned collections by creation time then the date in the name
+func isNewerVersion(col Collection, than Collection) bool {
+	if col.CreatedAt != than.CreatedAt {
+		return col.CreatedAt > than.CreatedAt
+	}
+	return col.Name > than.Name
+}
+
+// sortCollectionVersions : orders versioned collections newest first
+func sortCollectionVersions(cols []Collection) {
+	sort.SliceStable(cols, func(i, j int) bool {
+		return isNewerVersion(cols[i], cols[j])
+	})
+}
+
 func (d *baseClient[T]) resolveColName() string {
-	colName := conditional.Ternary(d.colName != "", d.colName, d.getCollectionName())
+	// not a ternary , the model name can't be computed for clients without a model (any)
+	colName := d.colName
+	if colName == "" {
+		colName = d.getCollectionName()
+	}
 	if !d.isNotAliased {
-		_, al := d.GetAliasCached(colName)
-		colName = al.CollectionName
+		// no alias , the name is used as is (so a missing collection is reported by typesense instead of searching "")
+		if exists, al := d.GetAliasCached(colName); exists && al.CollectionName != "" {
+			colName = al.CollectionName
+		}
 	}
 	return colName
 }
@@ -78,17 +110,44 @@ func (m *baseClient[T]) ModelToJSONLines(mod []*T) []byte {
 	return buf.Bytes()
 }
 
+// exportStream : streams the jsonl export of a collection , caller must close the reader
+func (m *baseClient[T]) exportStream(colName string, params map[string]string) (io.ReadCloser, error) {
+	res, err := m.Req().
+		SetDoNotParseResponse(true).
+		SetQueryParams(params).
+		Get(fmt.Sprintf("/collections/%s/documents/export", colName))
+	if err != nil {
+		return nil, err
+	}
+	body := res.RawBody()
+	if !http2.StatusIsSuccess(res.StatusCode()) {
+		defer body.Close()
+		errBody, _ := io.ReadAll(body)
+		return nil, typesenseToError(errBody, res.StatusCode())
+	}
+	return body, nil
+}
+
+// importJSONLines : imports jsonl into a collection and errors if any of the lines were rejected
+func (m *baseClient[T]) importJSONLines(colName string, action string, jsonLines []byte) error {
+	_, err := m.importWithResults(colName, map[string]string{"action": action}, jsonLines)
+	return err
+}
+
 // GetAlias : gets an alias label and returns back collection name
 func (m *baseClient[T]) GetAlias(aliasName string) (doesExist bool, alias Alias) {
 	res, err := m.Req().
 		SetResult(&alias).
-		Get(fmt.Sprintf("/alias/%s", aliasName))
+		Get(fmt.Sprintf("/aliases/%s", aliasName))
 	return err == nil && res.StatusCode() == http.StatusOK, alias
 }
 
 // GetAlias : gets an alias label and returns back collection name
 func (m *baseClient[T]) GetAliasCached(aliasName string) (doesExist bool, alias Alias) {
+	// clients are used from many goroutines (ie the bulk indexer workers)
+	m.mu.Lock()
 	colName := m.aliasCache[aliasName]
+	m.mu.Unlock()
 	if colName != "" {
 		alias.CollectionName = colName
 		alias.Name = aliasName
@@ -103,6 +162,27 @@ func (m *baseClient[T]) GetAliasCached(aliasName string) (doesExist bool, alias
 	return exists, alias
 }
 
+// forgetAlias : drops the cached collection name of an alias (after the alias was moved)
+func (m *baseClient[T]) forgetAlias(aliasName string) {
+	m.mu.Lock()
+	delete(m.aliasCache, aliasName)
+	m.mu.Unlock()
+}
+
+// GetCollections : lists all the collections in typesense
+func (m *baseClient[T]) GetCollections() ([]Collection, error) {
+	var cols []Collection
+	res, err := m.Req().
+		SetResult(&cols).
+		Get("/collections")
+	if err != nil {
+		return nil, err
+	} else if !http2.StatusIsSuccess(res.StatusCode()) {
+		return nil, typesenseToError(res.Body(), res.StatusCode())
+	}
+	return cols, nil
+}
+
 // GetCollection : gets a collection and checks if it exists
 func (m *baseClient[T]) GetCollection(collection string) (doesExist bool, col Collection) {
 	res, err := m.Req().
@@ -111,40 +191,6 @@ func (m *baseClient[T]) GetCollection(collection string) (doesExist bool, col Co
 	return err == nil && res.StatusCode() == http.StatusOK, col
 }
 
-func (m *baseClient[T]) golangToTypesenseType(field reflector.ObjField) (typ string, err error) {
-	fieldName, _ := field.Tag("db")
-	goType := field.Type().Name()
-	if goType == "" {
-		goType = field.Type().Elem().Name()
-	}
-	switch goType {
-	case "Time":
-		return "int64", nil
-	case "bool":
-		return "bool", nil
-	case "string":
-		return "string", nil
-	case "[]string":
-		return "string[]", nil
-	case "int64":
-		return "int64", nil
-	case "float32":
-		return "float", nil
-	case "float64":
-		return "float", nil
-	case "int32":
-		fallthrough
-	case "int16":
-		fallthrough
-	case "int8":
-		fallthrough
-	case "int":
-		return "int64 ", nil
-	default:
-		return "", fmt.Errorf("Typesense : Unsupported field type %s for %s field", goType, fieldName)
-	}
-}
-
 func newBaseClient[T any](apiKey string, host string, logging bool) *baseClient[T] {
 	return &baseClient[T]{
 		r:          newHTTPClient(apiKey, host, logging),
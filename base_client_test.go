@@ -0,0 +1,66 @@
+package typesense
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestGetAliasCachedConcurrent(t *testing.T) {
+	server := newImportTestServer(t)
+	server.aliasTo = "import_test_doc_v1"
+	client := newBaseClient[importTestDoc]("key", server.URL, false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if colName := client.resolveColName(); colName != "import_test_doc_v1" {
+					t.Errorf("resolveColName() = %s", colName)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				client.forgetAlias("import_test_doc")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestIsCollectionVersionOf(t *testing.T) {
+	tests := []struct {
+		colName string
+		want    bool
+	}{
+		{colName: "my_model_2022-10-10_a", want: true},
+		{colName: "my_model_2022-10-10_8BnZ3aXk9fWq", want: true},
+		{colName: "my_model", want: false},
+		{colName: "my_model_other", want: false},
+		{colName: "my_model_2022-10-10", want: false},
+		{colName: "my_model_2022-10-10_", want: false},
+		{colName: "my_model_2022-1-10_a", want: false},
+		{colName: "my_model_2022-10-10_a-b", want: false},
+		{colName: "my_model_x_2022-10-10_a", want: false},
+		{colName: "other_my_model_2022-10-10_a", want: false},
+		{colName: "mymodel_2022-10-10_a", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.colName, func(t *testing.T) {
+			if got := isCollectionVersionOf("my_model", test.colName); got != test.want {
+				t.Fatalf("isCollectionVersionOf(my_model, %s) = %v", test.colName, got)
+			}
+		})
+	}
+	// the alias is matched literally , not as a pattern
+	if isCollectionVersionOf("my.model", "myxmodel_2022-10-10_a") {
+		t.Fatal("the alias name should be quoted")
+	}
+	migration := NewManualMigration("key", "http://localhost", false)
+	if colName := migration.VersionCollectionName("my_model"); !isCollectionVersionOf("my_model", colName) {
+		t.Fatalf("VersionCollectionName() = %s is not a version", colName)
+	}
+}
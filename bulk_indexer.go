@@ -0,0 +1,330 @@
+package typesense
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultBulkBatchSize     = 1000
+	defaultBulkBatchBytes    = 5 << 20 // 5MB
+	defaultBulkFlushInterval = time.Second
+	defaultBulkWorkers       = 2
+	defaultBulkRetryBackoff  = 500 * time.Millisecond
+)
+
+// ErrBulkIndexerClosed : returned by Add once the bulk indexer is closing
+var ErrBulkIndexerClosed = errors.New("Typesense : bulk indexer is closed")
+
+// BulkIndexerConfig : batching / concurrency of a BulkIndexer , zero values use the defaults
+type BulkIndexerConfig[T any] struct {
+	// Action : the import action (default DocumentActionUpsert)
+	Action string
+	// BatchSize : max documents per batch (default 1000)
+	BatchSize int
+	// BatchBytes : max json bytes per batch (default 5MB)
+	BatchBytes int
+	// FlushInterval : a batch that isn't full is sent after this long (default 1s)
+	FlushInterval time.Duration
+	// Workers : batches sent to typesense at the same time (default 2)
+	Workers int
+	// MaxRetries : times a batch is sent again when the request fails with a connection error or a 5xx / 429 .
+	// other errors (ie a missing collection) are not retried , rejected documents are not retried either , they go to OnFailure
+	MaxRetries int
+	// RetryBackoff : wait before the first retry , doubled on every retry (default 500ms) . the wait is cut short
+	// (and the batch goes to OnError) when the context given to Close is done
+	RetryBackoff time.Duration
+	// OnFailure : called for every document typesense rejected (from the worker goroutines)
+	OnFailure func(doc *T, result ImportResult)
+	// OnError : called with the documents of a batch that could not be sent after the retries (from the worker goroutines)
+	OnError func(err error, docs []*T)
+}
+
+// BulkIndexerStats : counters of a BulkIndexer
+type BulkIndexerStats struct {
+	// Added : documents added
+	Added uint64
+	// Indexed : documents typesense accepted
+	Indexed uint64
+	// Failed : documents typesense rejected or that could not be sent
+	Failed uint64
+	// Retried : batch retries
+	Retried uint64
+	// Batches : batches sent
+	Batches uint64
+	// Bytes : json bytes sent
+	Bytes uint64
+}
+
+// BulkIndexer : indexes documents in batches from a pool of workers , Add blocks when the workers can't keep up
+//
+// Example :
+//			indexer := typesense.NewBulkIndexer(docClient, typesense.BulkIndexerConfig[User]{
+//				BatchSize: 500,
+//				Workers:   4,
+//				OnFailure: func(user *User, result typesense.ImportResult) {
+//					log.Printf("user %s rejected : %s", result.ID, result.Error)
+//				},
+//			})
+//			for change := range changes {
+//				err := indexer.Add(ctx, change.User)
+//				if err != nil {
+//					break
+//				}
+//			}
+//			err := indexer.Close(ctx) // flushes what's left
+//			log.Printf("%+v", indexer.Stats())
+//
+type BulkIndexer[T any] struct {
+	// counters first (64 bit alignment for atomics on 32 bit platforms)
+	stats BulkIndexerStats
+
+	client  IDocumentClient[T]
+	config  BulkIndexerConfig[T]
+	queue   chan bulkItem[T]
+	batches chan bulkBatch[T]
+	closing chan struct{}
+	aborted chan struct{}
+	done    chan struct{}
+
+	// queueMu : Add holds it (read) while sending to the queue , Close takes it to close the queue once no Add is in flight
+	queueMu     sync.RWMutex
+	queueClosed bool
+
+	closeOnce sync.Once
+	abortOnce sync.Once
+	errMu     sync.Mutex
+	err       error
+}
+
+type bulkItem[T any] struct {
+	doc  *T
+	line []byte
+}
+
+type bulkBatch[T any] struct {
+	docs []*T
+	body []byte
+}
+
+// NewBulkIndexer : starts a bulk indexer on top of a document client (the client's collection / alias is used)
+func NewBulkIndexer[T any](client IDocumentClient[T], config BulkIndexerConfig[T]) *BulkIndexer[T] {
+	if config.Action == "" {
+		config.Action = DocumentActionUpsert
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBulkBatchSize
+	}
+	if config.BatchBytes <= 0 {
+		config.BatchBytes = defaultBulkBatchBytes
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaultBulkFlushInterval
+	}
+	if config.Workers <= 0 {
+		config.Workers = defaultBulkWorkers
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaultBulkRetryBackoff
+	}
+	b := &BulkIndexer[T]{
+		client:  client,
+		config:  config,
+		queue:   make(chan bulkItem[T], config.BatchSize),
+		batches: make(chan bulkBatch[T]),
+		closing: make(chan struct{}),
+		aborted: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range b.batches {
+				b.send(batch)
+			}
+		}()
+	}
+	go func() {
+		b.batch()
+		close(b.batches)
+		wg.Wait()
+		close(b.done)
+	}()
+	return b
+}
+
+// Add : queues the document , blocks while the queue is full (backpressure) until the context is done
+func (b *BulkIndexer[T]) Add(ctx context.Context, doc *T) error {
+	line, err := json.Marshal(doc)
+	if err != nil {
+		return errors.Wrap(err, "Typesense : could not encode the document")
+	}
+	select {
+	case <-b.closing:
+		return ErrBulkIndexerClosed
+	default:
+	}
+	b.queueMu.RLock()
+	defer b.queueMu.RUnlock()
+	if b.queueClosed {
+		return ErrBulkIndexerClosed
+	}
+	// the queue is only closed once this returns , a queued document is always batched
+	select {
+	case b.queue <- bulkItem[T]{doc: doc, line: line}:
+		atomic.AddUint64(&b.stats.Added, 1)
+		return nil
+	case <-b.closing:
+		return ErrBulkIndexerClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close : flushes the queued documents and waits for the workers , returns the first batch error (see OnError)
+// or the context error if the context is done first (pending retries are given up then)
+func (b *BulkIndexer[T]) Close(ctx context.Context) error {
+	b.closeOnce.Do(func() {
+		// unblocks the Adds waiting on a full queue
+		close(b.closing)
+		go func() {
+			b.queueMu.Lock()
+			defer b.queueMu.Unlock()
+			b.queueClosed = true
+			close(b.queue)
+		}()
+	})
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		b.abortOnce.Do(func() { close(b.aborted) })
+		return ctx.Err()
+	}
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+	return b.err
+}
+
+// Stats : a snapshot of the counters
+func (b *BulkIndexer[T]) Stats() BulkIndexerStats {
+	return BulkIndexerStats{
+		Added:   atomic.LoadUint64(&b.stats.Added),
+		Indexed: atomic.LoadUint64(&b.stats.Indexed),
+		Failed:  atomic.LoadUint64(&b.stats.Failed),
+		Retried: atomic.LoadUint64(&b.stats.Retried),
+		Batches: atomic.LoadUint64(&b.stats.Batches),
+		Bytes:   atomic.LoadUint64(&b.stats.Bytes),
+	}
+}
+
+// batch : groups the queued documents into batches by count / bytes / flush interval until the queue is closed
+func (b *BulkIndexer[T]) batch() {
+	ticker := time.NewTicker(b.config.FlushInterval)
+	defer ticker.Stop()
+
+	var docs []*T
+	var body bytes.Buffer
+	flush := func() {
+		if len(docs) == 0 {
+			return
+		}
+		b.batches <- bulkBatch[T]{docs: docs, body: append([]byte(nil), body.Bytes()...)}
+		docs = nil
+		body.Reset()
+	}
+	add := func(item bulkItem[T]) {
+		if len(docs) > 0 && body.Len()+len(item.line)+1 > b.config.BatchBytes {
+			flush()
+		}
+		if len(docs) > 0 {
+			body.WriteByte('\n')
+		}
+		body.Write(item.line)
+		docs = append(docs, item.doc)
+		if len(docs) >= b.config.BatchSize {
+			flush()
+		}
+	}
+
+	for {
+		select {
+		case item, ok := <-b.queue:
+			if !ok {
+				flush()
+				return
+			}
+			add(item)
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// send : imports a batch , retrying the request when it fails with an error worth retrying
+func (b *BulkIndexer[T]) send(batch bulkBatch[T]) {
+	backoff := b.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		results, err := b.client.ImportManyWithResults(batch.body, b.config.Action)
+		var rejected *ImportError
+		if err == nil || errors.As(err, &rejected) {
+			atomic.AddUint64(&b.stats.Batches, 1)
+			atomic.AddUint64(&b.stats.Bytes, uint64(len(batch.body)))
+			b.report(batch, results)
+			return
+		}
+		if attempt >= b.config.MaxRetries || !isRetryableImportError(err) {
+			b.fail(batch, err)
+			return
+		}
+		atomic.AddUint64(&b.stats.Retried, 1)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-b.aborted:
+			timer.Stop()
+			b.fail(batch, errors.Wrap(err, "Typesense : bulk indexer closed before the batch could be retried"))
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// fail : the batch could not be sent
+func (b *BulkIndexer[T]) fail(batch bulkBatch[T], err error) {
+	atomic.AddUint64(&b.stats.Failed, uint64(len(batch.docs)))
+	b.setErr(err)
+	if b.config.OnError != nil {
+		b.config.OnError(err, batch.docs)
+	}
+}
+
+// report : counts the results of a batch (same order as the documents) and calls OnFailure for the rejected ones
+func (b *BulkIndexer[T]) report(batch bulkBatch[T], results []ImportResult) {
+	for i, doc := range batch.docs {
+		if i < len(results) && !results[i].Success {
+			atomic.AddUint64(&b.stats.Failed, 1)
+			if b.config.OnFailure != nil {
+				b.config.OnFailure(doc, results[i])
+			}
+			continue
+		}
+		atomic.AddUint64(&b.stats.Indexed, 1)
+	}
+}
+
+func (b *BulkIndexer[T]) setErr(err error) {
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+	if b.err == nil {
+		b.err = err
+	}
+}
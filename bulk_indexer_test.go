@@ -0,0 +1,175 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBulkIndexer(t *testing.T) {
+	server := newImportTestServer(t)
+	// every worker resolves the alias through the shared alias cache
+	server.aliasTo = "import_test_doc_v1"
+	client := NewDocumentClient[importTestDoc]("key", server.URL, false)
+
+	var failedMu sync.Mutex
+	var failed []string
+	indexer := NewBulkIndexer(client, BulkIndexerConfig[importTestDoc]{
+		BatchSize: 7,
+		Workers:   8,
+		OnFailure: func(doc *importTestDoc, result ImportResult) {
+			failedMu.Lock()
+			defer failedMu.Unlock()
+			failed = append(failed, doc.ID)
+		},
+	})
+	for i := 0; i < 500; i++ {
+		err := indexer.Add(context.Background(), &importTestDoc{ID: fmt.Sprint(i), Bad: i%100 == 0})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := indexer.Close(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := indexer.Stats()
+	if stats.Added != 500 || stats.Indexed != 495 || stats.Failed != 5 || stats.Retried != 0 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if stats.Batches != uint64(atomic.LoadInt64(&server.imports)) || stats.Batches < 72 {
+		t.Fatalf("batches = %d , imports = %d", stats.Batches, server.imports)
+	}
+	if len(failed) != 5 {
+		t.Fatalf("failed = %v", failed)
+	}
+	if len(server.receivedIDs()) != 500 {
+		t.Fatalf("received %d documents , want 500", len(server.receivedIDs()))
+	}
+	if err := indexer.Add(context.Background(), &importTestDoc{ID: "late"}); !errors.Is(err, ErrBulkIndexerClosed) {
+		t.Fatalf("Add after Close = %v", err)
+	}
+}
+
+func TestBulkIndexerAddDuringClose(t *testing.T) {
+	for run := 0; run < 20; run++ {
+		server := newImportTestServer(t)
+		client := NewDocumentClient[importTestDoc]("key", server.URL, false)
+		indexer := NewBulkIndexer(client, BulkIndexerConfig[importTestDoc]{BatchSize: 3, Workers: 2})
+
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; ; i++ {
+					err := indexer.Add(context.Background(), &importTestDoc{ID: fmt.Sprintf("%d-%d", g, i)})
+					if errors.Is(err, ErrBulkIndexerClosed) {
+						return
+					} else if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}(g)
+		}
+		time.Sleep(time.Millisecond)
+		err := indexer.Close(context.Background())
+		wg.Wait()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// every accepted document was sent
+		stats := indexer.Stats()
+		if stats.Added != stats.Indexed+stats.Failed || int(stats.Added) != len(server.receivedIDs()) {
+			t.Fatalf("stats = %+v , received %d", stats, len(server.receivedIDs()))
+		}
+	}
+}
+
+func TestBulkIndexerRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []int
+		wantRetried uint64
+		wantImports int64
+		wantErr     bool
+	}{
+		{name: "server error retried", statuses: []int{http.StatusServiceUnavailable, http.StatusInternalServerError}, wantRetried: 2, wantImports: 3},
+		{name: "too many requests retried", statuses: []int{http.StatusTooManyRequests}, wantRetried: 1, wantImports: 2},
+		{name: "retries exhausted", statuses: []int{503, 503, 503, 503}, wantRetried: 2, wantImports: 3, wantErr: true},
+		{name: "missing collection not retried", statuses: []int{http.StatusNotFound}, wantImports: 1, wantErr: true},
+		{name: "bad request not retried", statuses: []int{http.StatusBadRequest}, wantImports: 1, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newImportTestServer(t)
+			server.failNext(test.statuses...)
+			client := NewDocumentClient[importTestDoc]("key", server.URL, false)
+
+			var onError []*importTestDoc
+			indexer := NewBulkIndexer(client, BulkIndexerConfig[importTestDoc]{
+				Workers:      1,
+				MaxRetries:   2,
+				RetryBackoff: time.Millisecond,
+				OnError: func(err error, docs []*importTestDoc) {
+					onError = append(onError, docs...)
+				},
+			})
+			_ = indexer.Add(context.Background(), &importTestDoc{ID: "1"})
+			_ = indexer.Add(context.Background(), &importTestDoc{ID: "2"})
+			err := indexer.Close(context.Background())
+
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Close() = %v , wantErr %v", err, test.wantErr)
+			}
+			stats := indexer.Stats()
+			if stats.Retried != test.wantRetried || atomic.LoadInt64(&server.imports) != test.wantImports {
+				t.Fatalf("stats = %+v , imports = %d", stats, server.imports)
+			}
+			if test.wantErr && (len(onError) != 2 || stats.Failed != 2) {
+				t.Fatalf("OnError docs = %d , stats = %+v", len(onError), stats)
+			}
+			if !test.wantErr && stats.Indexed != 2 {
+				t.Fatalf("stats = %+v", stats)
+			}
+		})
+	}
+}
+
+func TestBulkIndexerCloseAbortsBackoff(t *testing.T) {
+	server := newImportTestServer(t)
+	server.failNext(http.StatusServiceUnavailable)
+	client := NewDocumentClient[importTestDoc]("key", server.URL, false)
+
+	gaveUp := make(chan error, 1)
+	indexer := NewBulkIndexer(client, BulkIndexerConfig[importTestDoc]{
+		MaxRetries:   5,
+		RetryBackoff: time.Hour,
+		OnError: func(err error, docs []*importTestDoc) {
+			gaveUp <- err
+		},
+	})
+	_ = indexer.Add(context.Background(), &importTestDoc{ID: "1"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := indexer.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close() = %v", err)
+	}
+	select {
+	case err := <-gaveUp:
+		if err == nil {
+			t.Fatal("want the batch error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the retry backoff was not cut short")
+	}
+}
@@ -7,6 +7,8 @@ func NewClient[T any](apiKey string, host string, logging bool) IClient[T] {
 		doc:       NewDocumentClient[T](apiKey, host, logging),
 		search:    NewSearchClient[T](apiKey, host, logging),
 		cluster:   NewClusterClient(apiKey, host, logging),
+		multi:     NewMultiSearchClient(apiKey, host, logging),
+		backup:    NewBackupClient(apiKey, host, logging),
 	}
 }
 
@@ -18,6 +20,8 @@ func NewClientNoGeneric(apiKey string, host string, logging bool) IClient[any] {
 		doc:       NewDocumentClient[any](apiKey, host, logging),
 		search:    NewSearchClient[any](apiKey, host, logging),
 		cluster:   NewClusterClient(apiKey, host, logging),
+		multi:     NewMultiSearchClient(apiKey, host, logging),
+		backup:    NewBackupClient(apiKey, host, logging),
 	}
 }
 
@@ -31,6 +35,10 @@ type IClient[T any] interface {
 	Search() ISearchClient[T]
 	// Cluster : return back cluster client
 	Cluster() IClusterClient
+	// MultiSearch : return back multi search client
+	MultiSearch() IMultiSearchClient
+	// Backup : return back backup client
+	Backup() IBackupClient
 }
 
 // Client : General Client that contains all operations supported by typesense
@@ -41,6 +49,8 @@ type Client[T any] struct {
 	doc       IDocumentClient[T]
 	search    ISearchClient[T]
 	cluster   IClusterClient
+	multi     IMultiSearchClient
+	backup    IBackupClient
 }
 
 // Migration : returns back migration client
@@ -62,3 +72,13 @@ func (c Client[T]) Search() ISearchClient[T] {
 func (c Client[T]) Cluster() IClusterClient {
 	return c.cluster
 }
+
+// MultiSearch : return back multi search client
+func (c Client[T]) MultiSearch() IMultiSearchClient {
+	return c.multi
+}
+
+// Backup : return back backup client
+func (c Client[T]) Backup() IBackupClient {
+	return c.backup
+}
@@ -22,7 +22,7 @@ type UserData struct {
 	Email string `json:"email" tsense_required:"true" tsense_facet:"1"`
 	// default sorting
 	GPA float32 `json:"gpa" tsense_default_sort:"1"`
-	// default type any int is int64 , you can always override this
+	// default type for int / int64 is int64 (int32 for smaller ints) , you can always override this
 	Visit     int32 `json:"visit" tsense_type:"int32"`
 	IsDeleted bool  `json:"is_deleted"  tsense_required:"true"`
 	// by default time.Time is not supported since time isn't supported in typesense
@@ -8,12 +8,18 @@
 //
 // - Search Client    => Allows advanced search
 //
+// - Multi Search Client => Sends many searches (on different collections / models) in one request
+//
 // - Document Client  => Allows indexing (inserting / del / update) Documents + simple gets by id export ..etc
 //
 // - Cluster Client   => Manages cluster / gets health and other metrics
 //
+// - Backup Client    => Backs up / restores collections on any afero file system
+//
 // - Main Client      => A facade for all the clients the fat client that has everything if you're lazy like me
 //
+// The filter sub package builds filter_by expressions (escaping the values for you) for the search and document clients
+//
 // Additionally there are an interfaces for each client as well as a `mock` implementations of the interfaces if you need
 // it in a test setting  (built using testify mock package) . However , You are responsible for breaking changes in your testing setup.
 //
@@ -77,7 +83,7 @@ const (
 	// Example :
 	//           // your model
 	//			type Model struct {
-	//				Field int8 `tsense_type:"int32"` // this will tell typesense you want
+	//				Field int `tsense_type:"int32"` // this will tell typesense you want
 	//												 // this field to override the type instead of the auto type (int64)
 	//			}
 	//
@@ -92,4 +98,40 @@ const (
 	//			}
 	//
 	TagDefaultSort = "tsense_default_sort"
+	// TagNumDim : attach this to your float slice field tsense_num_dim
+	//
+	// Example :
+	//           // your model
+	//			type Model struct {
+	//				Embedding []float32 `tsense_num_dim:"384"` // this will tell typesense the field is a vector
+	//												   // with 384 dimensions (you provide the vectors)
+	//			}
+	//
+	TagNumDim = "tsense_num_dim"
+	// TagVecDist : attach this to your vector field tsense_vec_dist
+	//
+	// Example :
+	//           // your model
+	//			type Model struct {
+	//				Embedding []float32 `tsense_num_dim:"384" tsense_vec_dist:"ip"` // this will tell typesense to use the
+	//																		   // inner product distance (cosine by default)
+	//			}
+	//
+	TagVecDist = "tsense_vec_dist"
+	// TagEmbedFrom : attach this to your float slice field tsense_embed_from
+	//
+	// Example :
+	//           // your model
+	//			type Model struct {
+	//				Name        string    `json:"name"`
+	//				Description string    `json:"description"`
+	//				Embedding   []float32 `json:"embedding,omitempty" tsense_embed_from:"name,description" tsense_embed_model:"ts/all-MiniLM-L12-v2"`
+	//				// this will tell typesense to generate the embedding from the name + description fields
+	//			}
+	//
+	TagEmbedFrom = "tsense_embed_from"
+	// TagEmbedModel : attach this to your embedding field tsense_embed_model (see TagEmbedFrom)
+	//
+	// models that need credentials (ie openai/text-embedding-ada-002) can be configured with SetEmbedModelConfig
+	TagEmbedModel = "tsense_embed_model"
 )
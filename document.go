@@ -2,12 +2,13 @@ package typesense
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 
+	"github.com/baderkha/typesense/filter"
 	"github.com/baderkha/typesense/pkg/conditional"
 	http2 "github.com/baderkha/typesense/pkg/http"
 	"github.com/pkg/errors"
-	"github.com/spf13/afero"
 )
 
 const (
@@ -17,6 +18,8 @@ const (
 	DocumentActionUpdate = "update"
 	// DocumentActionUpsert : create a new document / documents , error if already exists it will merge
 	DocumentActionEmplace = "emplace"
+	// DocumentActionCreate : create new documents , error if the id already exists
+	DocumentActionCreate = "create"
 
 	// DocumentDirtyStratCORreject : Attempt coercion of the field's value to previously inferred type.
 	// If coercion fails, reject the write outright with an error message.
@@ -55,6 +58,29 @@ type IDocumentClient[T any] interface {
 	ExportAll() ([]byte, error) // exports as []byte (string represenation of )
 	// ExportAll : Export all the document with a query filter as a byte slice (string represntation of jsonL)
 	ExportAllWithQuery(query string) ([]byte, error)
+	// ExportAllWithFilter : Export all the documents matching a filter built with the filter package
+	//
+	// Example :
+	//				docs, err := docClient.ExportAllWithFilter(filter.Field("country").Exact("France"))
+	ExportAllWithFilter(expr filter.Expr) ([]byte, error)
+	// ExportStream : streams the export and decodes the documents one at a time (the export is never held in memory as a whole)
+	//
+	// Example :
+	//				it, err := docClient.ExportStream(&typesense.ExportOptions{
+	//					Filter:        filter.Field("country").Exact("France"),
+	//					ExcludeFields: []string{"embedding"},
+	//				})
+	//				if err != nil {
+	//					log.Fatal(err)
+	//				}
+	//				defer it.Close()
+	//				for it.Next() {
+	//					user := it.Document()
+	//				}
+	//				if err := it.Err(); err != nil {
+	//					log.Fatal(err)
+	//				}
+	ExportStream(opts *ExportOptions) (*ExportIterator[T], error)
 	// Index : create / add new document . can also do upserts
 	Index(m *T) error
 	// Update : update existing document completley. will error out if required details are missing or if document not found
@@ -63,10 +89,28 @@ type IDocumentClient[T any] interface {
 	DeleteById(id string) error
 	// DeleteManyWithQuery : delete more than 1 with a query criteria
 	DeleteManyWithQuery(query string) error
+	// DeleteManyWithFilter : delete all the documents matching a filter built with the filter package
+	//
+	// Example :
+	//				err := docClient.DeleteManyWithFilter(filter.Field("created_at").Lt(time.Now().AddDate(0, -1, 0)))
+	DeleteManyWithFilter(expr filter.Expr) error
 	// IndexMany : create multiple documents (transforms them to jsonL behind the scenes)
 	IndexMany(m []*T, action string) error
-	// ImportMany : import many documents with json lines
+	// IndexManyReturnFailed : same as IndexMany , also returns the documents typesense rejected so they can be fixed and retried
+	//
+	// Example :
+	//				failed, err := docClient.IndexManyReturnFailed(users, typesense.DocumentActionUpsert)
+	//				var importErr *typesense.ImportError
+	//				if errors.As(err, &importErr) {
+	//					log.Println(importErr.FailedIDs)
+	//					// fix and retry the failed users
+	//				}
+	IndexManyReturnFailed(m []*T, action string) ([]*T, error)
+	// ImportMany : import many documents with json lines ,
+	// errors with an *ImportError listing the failed ids if typesense rejected any of the lines
 	ImportMany(jsonLines []byte, action string) error
+	// ImportManyWithResults : same as ImportMany , also returns the result of every line (same order as the lines)
+	ImportManyWithResults(jsonLines []byte, action string) ([]ImportResult, error)
 	// ImportManyFromFile : opens a file from path and sends it to your typesense backend
 	//
 	// This gives you the operatunity to specifc the file system to be used
@@ -97,6 +141,23 @@ type IDocumentClient[T any] interface {
 	//				}
 	//
 	ImportManyFromFile(path string, action string) error
+	// ImportStream : streams jsonl from the reader to typesense in chunks (see ImportStreamOptions) without reading it all
+	// in memory . returns the result of every line (same order as the lines) , errors with an *ImportError if any
+	// of the lines were rejected
+	//
+	// Example :
+	//				results, err := docClient.ImportStream(gzipReader, typesense.DocumentActionUpsert, &typesense.ImportStreamOptions{
+	//					ChunkLines:  5000,
+	//					Concurrency: 4,
+	//					OnlyFailed:  true,
+	//					Progress: func(p typesense.ImportProgress) {
+	//						log.Printf("imported %d lines (%d failed)", p.Lines, p.Failed)
+	//					},
+	//				})
+	//
+	ImportStream(r io.Reader, action string, opts *ImportStreamOptions) ([]ImportResult, error)
+	// ImportStreamFromFile : ImportStream from a file of the package file system (see OverrideFS)
+	ImportStreamFromFile(path string, action string, opts *ImportStreamOptions) ([]ImportResult, error)
 	// WithBatchSize : Override The batch Size for a local operation and not globally
 	WithBatchSize(batchSize int64) IDocumentClient[T]
 	// WithDirtyStrat : Override the dirty document strategy for a local operation and not globally
@@ -178,6 +239,13 @@ func (d *DocumentClient[T]) ExportAllWithQuery(query string) ([]byte, error) {
 	return res.Body(), nil
 
 }
+func (d *DocumentClient[T]) ExportAllWithFilter(expr filter.Expr) ([]byte, error) {
+	query, err := buildFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	return d.ExportAllWithQuery(query)
+}
 func (d *DocumentClient[T]) Index(m *T) error {
 	res, err := d.
 		Req().
@@ -238,34 +306,58 @@ func (d *DocumentClient[T]) DeleteManyWithQuery(query string) error {
 
 	return nil
 }
+func (d *DocumentClient[T]) DeleteManyWithFilter(expr filter.Expr) error {
+	query, err := buildFilter(expr)
+	if err != nil {
+		return err
+	}
+	// an empty filter_by would be rejected anyway , don't send it
+	if query == "" {
+		return errors.New("Typesense : delete needs a non empty filter")
+	}
+	return d.DeleteManyWithQuery(query)
+}
+
+// buildFilter : the filter_by string of an expression (empty for nil)
+func buildFilter(expr filter.Expr) (string, error) {
+	if expr == nil {
+		return "", nil
+	}
+	return expr.Build()
+}
 func (d *DocumentClient[T]) IndexMany(m []*T, action string) error {
 	return d.ImportMany(d.ModelToJSONLines(m), action)
 }
 
 func (d *DocumentClient[T]) ImportManyFromFile(path string, action string) error {
-	content, err := afero.ReadFile(fs, path)
-	if err != nil {
-		errors.Wrap(err, typesenseErrPrefix)
-	}
-	return d.ImportMany(content, action)
+	_, err := d.ImportStreamFromFile(path, action, &ImportStreamOptions{OnlyFailed: true})
+	return err
 }
 func (d *DocumentClient[T]) ImportMany(jsonLines []byte, action string) error {
-	res, err := d.
-		Req().
-		SetHeader("Content-Type", "text/plain").
-		SetBody(jsonLines).
-		SetQueryParam("action", action).
-		SetQueryParam("dirty_values", d.getDirtyStrat()).
-		SetQueryParam("batch_size", d.getBatchSize()).
-		Post(d.docRoute("import"))
-
-	if err != nil {
-		return err
-	} else if !http2.StatusIsSuccess(res.StatusCode()) {
-		return typesenseToError(res.Body(), res.StatusCode())
+	_, err := d.ImportManyWithResults(jsonLines, action)
+	return err
+}
+func (d *DocumentClient[T]) ImportManyWithResults(jsonLines []byte, action string) ([]ImportResult, error) {
+	return d.importWithResults(d.resolveColName(), d.importParams(action), jsonLines)
+}
+func (d *DocumentClient[T]) IndexManyReturnFailed(m []*T, action string) ([]*T, error) {
+	results, err := d.ImportManyWithResults(d.ModelToJSONLines(m), action)
+	var failed []*T
+	// results are in the same order as the documents
+	for i, result := range results {
+		if !result.Success && i < len(m) {
+			failed = append(failed, m[i])
+		}
 	}
+	return failed, err
+}
 
-	return nil
+func (d *DocumentClient[T]) importParams(action string) map[string]string {
+	return map[string]string{
+		"action":       action,
+		"dirty_values": d.getDirtyStrat(),
+		"batch_size":   d.getBatchSize(),
+	}
 }
 func (d *DocumentClient[T]) WithBatchSize(batchSize int64) IDocumentClient[T] {
 	var newDoc DocumentClient[T]
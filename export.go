@@ -0,0 +1,133 @@
+package typesense
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/baderkha/typesense/filter"
+	"github.com/pkg/errors"
+)
+
+// ExportOptions : which documents / fields an export streams , nil exports everything
+type ExportOptions struct {
+	// FilterBy : raw filter_by expression
+	FilterBy string
+	// Filter : filter built with the filter package (joined to FilterBy with && if both are set)
+	Filter filter.Expr
+	// IncludeFields : only export these fields
+	IncludeFields []string
+	// ExcludeFields : do not export these fields
+	ExcludeFields []string
+}
+
+func (o *ExportOptions) queryParams() (map[string]string, error) {
+	params := make(map[string]string)
+	if o == nil {
+		return params, nil
+	}
+	filterBy := o.FilterBy
+	if o.Filter != nil {
+		built, err := o.Filter.Build()
+		if err != nil {
+			return nil, err
+		}
+		if built != "" {
+			filterBy = appendFilter(filterBy, built)
+		}
+	}
+	if filterBy != "" {
+		params["filter_by"] = filterBy
+	}
+	if len(o.IncludeFields) > 0 {
+		params["include_fields"] = strings.Join(o.IncludeFields, ",")
+	}
+	if len(o.ExcludeFields) > 0 {
+		params["exclude_fields"] = strings.Join(o.ExcludeFields, ",")
+	}
+	return params, nil
+}
+
+// ExportIterator : decodes the documents of an export one at a time while the export streams in ,
+// created with IDocumentClient.ExportStream . Close it if you stop before the end
+type ExportIterator[T any] struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+	doc    *T
+	err    error
+	done   bool
+}
+
+func newExportIterator[T any](body io.ReadCloser) *ExportIterator[T] {
+	return &ExportIterator[T]{
+		body:   body,
+		reader: bufio.NewReader(body),
+	}
+}
+
+// Next : decodes the next document , false at the end of the export or on error (the export is closed then)
+func (it *ExportIterator[T]) Next() bool {
+	for !it.done {
+		line, readErr := it.reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			it.fail(errors.Wrap(readErr, typesenseErrPrefix))
+			return false
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			var doc T
+			err := json.Unmarshal(line, &doc)
+			if err != nil {
+				it.fail(errors.Wrap(err, "Typesense : could not decode an exported document"))
+				return false
+			}
+			it.doc = &doc
+			if readErr == io.EOF {
+				_ = it.Close()
+			}
+			return true
+		}
+		if readErr == io.EOF {
+			_ = it.Close()
+		}
+	}
+	return false
+}
+
+// Document : the current document
+func (it *ExportIterator[T]) Document() *T {
+	return it.doc
+}
+
+// Err : the error that stopped the iterator if any
+func (it *ExportIterator[T]) Err() error {
+	return it.err
+}
+
+// Close : stops the export
+func (it *ExportIterator[T]) Close() error {
+	if it.done {
+		return nil
+	}
+	it.done = true
+	return it.body.Close()
+}
+
+func (it *ExportIterator[T]) fail(err error) {
+	it.err = err
+	_ = it.Close()
+}
+
+func (d *DocumentClient[T]) ExportStream(opts *ExportOptions) (*ExportIterator[T], error) {
+	params, err := opts.queryParams()
+	if err != nil {
+		return nil, err
+	}
+	body, err := d.exportStream(d.resolveColName(), params)
+	if err != nil {
+		return nil, err
+	}
+	return newExportIterator[T](body), nil
+}
@@ -0,0 +1,235 @@
+package typesense
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/baderkha/typesense/filter"
+)
+
+// exportTestBody : an export body that records how many times it was closed
+type exportTestBody struct {
+	io.Reader
+	closed int
+}
+
+func (b *exportTestBody) Close() error {
+	b.closed++
+	return nil
+}
+
+func TestExportOptionsQueryParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *ExportOptions
+		want    map[string]string
+		wantErr string
+	}{
+		{name: "nil", opts: nil, want: map[string]string{}},
+		{name: "empty", opts: &ExportOptions{}, want: map[string]string{}},
+		{name: "filter by", opts: &ExportOptions{FilterBy: "rank:>1"}, want: map[string]string{"filter_by": "rank:>1"}},
+		{name: "filter", opts: &ExportOptions{Filter: filter.Field("brand").Exact("Nike")}, want: map[string]string{"filter_by": "brand:=`Nike`"}},
+		{
+			name: "filter by and filter",
+			opts: &ExportOptions{FilterBy: "rank:>1", Filter: filter.Field("brand").Exact("Nike")},
+			want: map[string]string{"filter_by": "rank:>1 && brand:=`Nike`"},
+		},
+		{
+			name: "filter by with a top level or",
+			opts: &ExportOptions{FilterBy: "rank:>1 || rank:<0", Filter: filter.Field("brand").Exact("Nike")},
+			want: map[string]string{"filter_by": "(rank:>1 || rank:<0) && brand:=`Nike`"},
+		},
+		{
+			name: "filter with a top level or",
+			opts: &ExportOptions{FilterBy: "rank:>1", Filter: filter.Or(filter.Field("brand").Exact("Nike"), filter.Field("brand").Exact("Puma"))},
+			want: map[string]string{"filter_by": "rank:>1 && (brand:=`Nike` || brand:=`Puma`)"},
+		},
+		{
+			name: "grouped or is left as is",
+			opts: &ExportOptions{FilterBy: "(rank:>1 || rank:<0) && in_stock:true", Filter: filter.Field("brand").Exact("Nike")},
+			want: map[string]string{"filter_by": "(rank:>1 || rank:<0) && in_stock:true && brand:=`Nike`"},
+		},
+		{
+			name: "fields",
+			opts: &ExportOptions{IncludeFields: []string{"id", "name"}, ExcludeFields: []string{"embedding"}},
+			want: map[string]string{"include_fields": "id,name", "exclude_fields": "embedding"},
+		},
+		{name: "filter error", opts: &ExportOptions{FilterBy: "rank:>1", Filter: filter.Ref(nil).Exact("Nike")}, wantErr: "cannot be nil"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.opts.queryParams()
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("err = %v , want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("got %v , want %v", got, test.want)
+			}
+			for key, want := range test.want {
+				if got[key] != want {
+					t.Fatalf("%s = %q , want %q", key, got[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestExportIterator(t *testing.T) {
+	tests := []struct {
+		name   string
+		export string
+		want   []string
+	}{
+		{name: "empty", export: "", want: nil},
+		{name: "trailing newline", export: "{\"id\":\"1\"}\n{\"id\":\"2\"}\n", want: []string{"1", "2"}},
+		{name: "no trailing newline", export: "{\"id\":\"1\"}\n{\"id\":\"2\"}", want: []string{"1", "2"}},
+		{name: "single line", export: `{"id":"1"}`, want: []string{"1"}},
+		{name: "blank lines", export: "\n{\"id\":\"1\"}\n\n  \r\n{\"id\":\"2\"}\r\n\n", want: []string{"1", "2"}},
+		{name: "only blank lines", export: "\n\n  \n", want: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := &exportTestBody{Reader: strings.NewReader(test.export)}
+			it := newExportIterator[importTestDoc](body)
+			var got []string
+			for it.Next() {
+				got = append(got, it.Document().ID)
+			}
+			if it.Err() != nil {
+				t.Fatal(it.Err())
+			}
+			if strings.Join(got, ",") != strings.Join(test.want, ",") {
+				t.Fatalf("got %v , want %v", got, test.want)
+			}
+			if it.Next() {
+				t.Fatal("Next() after the end should be false")
+			}
+			if err := it.Close(); err != nil || body.closed != 1 {
+				t.Fatalf("Close() = %v , body closed %d times", err, body.closed)
+			}
+		})
+	}
+}
+
+func TestExportIteratorErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    io.Reader
+		wantIDs []string
+		wantErr string
+	}{
+		{
+			name:    "decode error",
+			body:    strings.NewReader("{\"id\":\"1\"}\nnot json\n{\"id\":\"3\"}\n"),
+			wantIDs: []string{"1"},
+			wantErr: "could not decode an exported document",
+		},
+		{
+			name:    "wrong type",
+			body:    strings.NewReader(`{"id":1}`),
+			wantErr: "could not decode an exported document",
+		},
+		{
+			name:    "read error",
+			body:    io.MultiReader(strings.NewReader("{\"id\":\"1\"}\n"), failingReader{}),
+			wantIDs: []string{"1"},
+			wantErr: "disk on fire",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := &exportTestBody{Reader: test.body}
+			it := newExportIterator[importTestDoc](body)
+			var got []string
+			for it.Next() {
+				got = append(got, it.Document().ID)
+			}
+			if strings.Join(got, ",") != strings.Join(test.wantIDs, ",") {
+				t.Fatalf("got %v , want %v", got, test.wantIDs)
+			}
+			if it.Err() == nil || !strings.Contains(it.Err().Error(), test.wantErr) {
+				t.Fatalf("Err() = %v , want %q", it.Err(), test.wantErr)
+			}
+			// the iteration stops for good and the export is closed
+			if it.Next() || body.closed != 1 {
+				t.Fatalf("Next() after an error should be false , body closed %d times", body.closed)
+			}
+			_ = it.Close()
+			if body.closed != 1 {
+				t.Fatalf("body closed %d times", body.closed)
+			}
+		})
+	}
+}
+
+func TestExportIteratorClose(t *testing.T) {
+	body := &exportTestBody{Reader: strings.NewReader("{\"id\":\"1\"}\n{\"id\":\"2\"}\n")}
+	it := newExportIterator[importTestDoc](body)
+	if !it.Next() || it.Document().ID != "1" {
+		t.Fatal("expected the first document")
+	}
+	if err := it.Close(); err != nil || body.closed != 1 {
+		t.Fatalf("Close() = %v , body closed %d times", err, body.closed)
+	}
+	if it.Next() || it.Err() != nil {
+		t.Fatalf("Next() after Close should be false without an error , got %v", it.Err())
+	}
+}
+
+func TestExportStream(t *testing.T) {
+	var query string
+	server := newSearchTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/collections/import_test_doc/documents/export" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		query = r.URL.Query().Get("filter_by") + "|" + r.URL.Query().Get("include_fields")
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte("{\"id\":\"1\"}\n{\"id\":\"2\"}"))
+	})
+	client := NewDocumentClient[importTestDoc]("key", server.URL, false)
+
+	it, err := client.ExportStream(&ExportOptions{
+		FilterBy:      "rank:>1 || rank:<0",
+		Filter:        filter.Field("brand").Exact("Nike"),
+		IncludeFields: []string{"id"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	var got []string
+	for it.Next() {
+		got = append(got, it.Document().ID)
+	}
+	if it.Err() != nil || strings.Join(got, ",") != "1,2" {
+		t.Fatalf("got %v , err = %v", got, it.Err())
+	}
+	if want := "(rank:>1 || rank:<0) && brand:=`Nike`|id"; query != want {
+		t.Fatalf("query = %q , want %q", query, want)
+	}
+}
+
+func TestExportStreamErrors(t *testing.T) {
+	server := newSearchTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+	})
+	client := NewDocumentClient[importTestDoc]("key", server.URL, false)
+
+	_, err := client.ExportStream(nil)
+	if err == nil || !strings.Contains(err.Error(), "Not Found") {
+		t.Fatalf("ExportStream() = %v", err)
+	}
+	_, err = client.ExportStream(&ExportOptions{Filter: filter.Ref(nil).Exact("x")})
+	if err == nil || !strings.Contains(err.Error(), "cannot be nil") {
+		t.Fatalf("ExportStream() = %v", err)
+	}
+}
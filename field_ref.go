@@ -0,0 +1,144 @@
+package typesense
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldReference : a model field resolved to its typesense name , accepted by the filter / sort / query by builders
+type FieldReference interface {
+	// FieldName : the typesense field name , errors if the field is not part of the model schema
+	FieldName() (string, error)
+	// Sortable : the typesense field name , errors if the field can't be sorted on (tsense_sort / tsense_default_sort)
+	Sortable() (string, error)
+	// Facetable : the typesense field name , errors if the field can't be faceted on (tsense_facet)
+	Facetable() (string, error)
+}
+
+// FieldRef : a field of the model T resolved with the same tag logic as the schema (json name , db tag , snake case)
+// so renaming a json tag can't silently break your filters / sorts
+type FieldRef[T any] struct {
+	goPath        string
+	field         CollectionField
+	isDefaultSort bool
+	err           error
+}
+
+// Field : references a go field of the model T (use dots for nested structs , ie Address.City -> address.city)
+//
+// nested fields are only sortable / facetable with the tsense tags on the nested struct field , the schema then
+// declares them explicitly (ie address.city)
+//
+// Example :
+//			type Product struct {
+//				Brand string  `json:"brand" tsense_facet:"1"`
+//				Price float64 `json:"price" tsense_sort:"1"`
+//			}
+//			params := typesense.
+//				NewSearchParams().
+//				AddFilter(filter.Ref(typesense.Field[Product]("Brand")).Exact("Nike")).
+//				AddFacetByFields(typesense.Field[Product]("Brand")).
+//				AddSortByField(typesense.Field[Product]("Price"), typesense.SortAsc)
+//
+func Field[T any](goPath string) FieldRef[T] {
+	field, isDefaultSort, err := resolveModelField(reflect.TypeOf((*T)(nil)).Elem(), goPath)
+	return FieldRef[T]{
+		goPath:        goPath,
+		field:         field,
+		isDefaultSort: isDefaultSort,
+		err:           err,
+	}
+}
+
+// FieldName : the typesense field name
+func (f FieldRef[T]) FieldName() (string, error) {
+	return f.field.Name, f.err
+}
+
+// Schema : the typesense schema of the field
+func (f FieldRef[T]) Schema() (CollectionField, error) {
+	return f.field, f.err
+}
+
+// Sortable : the typesense field name , errors if the field has no tsense_sort / tsense_default_sort tag
+func (f FieldRef[T]) Sortable() (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	if !f.field.Sort && !f.isDefaultSort {
+		return "", fmt.Errorf("Typesense : field %s (%s) is not sortable , add the %s tag", f.goPath, f.field.Name, TagSort)
+	}
+	return f.field.Name, nil
+}
+
+// Facetable : the typesense field name , errors if the field has no tsense_facet tag
+func (f FieldRef[T]) Facetable() (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	if !f.field.Facet {
+		return "", fmt.Errorf("Typesense : field %s (%s) is not facetable , add the %s tag", f.goPath, f.field.Name, TagFacet)
+	}
+	return f.field.Name, nil
+}
+
+// resolveModelField : walks a dotted go field path through the model (and its nested structs) to the typesense field
+func resolveModelField(modelType reflect.Type, goPath string) (field CollectionField, isDefaultSort bool, err error) {
+	structType := derefType(modelType)
+	segments := strings.Split(goPath, ".")
+	names := make([]string, 0, len(segments))
+	var rootSchema *modelSchema
+	for i, segment := range segments {
+		if structType.Kind() != reflect.Struct {
+			return field, false, fmt.Errorf("Typesense : cannot reference field %s , %s is not a struct", goPath, structType)
+		}
+		structField, ok := structType.FieldByName(segment)
+		if !ok || !structField.IsExported() {
+			return field, false, fmt.Errorf("Typesense : %s has no exported field %s", structType, segment)
+		}
+		name, _, skip := schemaFieldName(structField)
+		if skip {
+			return field, false, fmt.Errorf("Typesense : field %s is not part of the schema (json:\"-\")", goPath)
+		}
+		names = append(names, name)
+
+		// the id is managed by typesense , it's not in the schema but can still be filtered on
+		if i == 0 && name == "id" && len(segments) == 1 {
+			return CollectionField{Name: name, Type: "string", Index: true}, false, nil
+		}
+		schema, err := newModelSchema(structType)
+		if err != nil {
+			return field, false, err
+		}
+		if i == 0 {
+			rootSchema = schema
+		}
+		isLast := i == len(segments)-1
+		// nested fields with tsense tags are explicit fields of the model schema
+		if isLast && i > 0 {
+			if nestedField, ok := rootSchema.field(strings.Join(names, ".")); ok {
+				return nestedField, false, nil
+			}
+		}
+		colField, ok := schema.field(name)
+		if !ok {
+			return field, false, fmt.Errorf("Typesense : field %s is not part of the %s schema", goPath, structType)
+		}
+		if isLast && i > 0 {
+			// the other nested fields are flattened by typesense , they can be filtered / queried on but not sorted / faceted on
+			colField.Name = strings.Join(names, ".")
+			colField.Sort = false
+			colField.Facet = false
+			return colField, false, nil
+		} else if isLast {
+			return colField, schema.DefaultSort == name, nil
+		}
+
+		structType = derefType(structField.Type)
+		if structType.Kind() == reflect.Slice || structType.Kind() == reflect.Array {
+			structType = derefType(structType.Elem())
+		}
+	}
+	return field, false, fmt.Errorf("Typesense : cannot reference an empty field path")
+}
@@ -0,0 +1,163 @@
+package typesense
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/baderkha/typesense/filter"
+)
+
+type fieldRefTestAddress struct {
+	City string  `json:"city" tsense_facet:"1"`
+	Zip  string  `json:"zip"`
+	Lat  float64 `json:"lat" tsense_sort:"1"`
+}
+
+type fieldRefTestProduct struct {
+	ID       string                `json:"id"`
+	Name     string                `json:"name"`
+	Brand    string                `json:"brand" tsense_facet:"1"`
+	Price    float64               `json:"price" tsense_sort:"1"`
+	Rank     int                   `json:"rank" tsense_default_sort:"1"`
+	SKU      string                `db:"sku"`
+	Secret   string                `json:"-"`
+	Address  fieldRefTestAddress   `json:"address"`
+	Branches []fieldRefTestAddress `json:"branches"`
+	Meta     map[string]string     `json:"meta"`
+	internal string
+}
+
+func TestField(t *testing.T) {
+	tests := []struct {
+		goPath        string
+		wantName      string
+		wantType      string
+		wantSortable  bool
+		wantFacetable bool
+		wantErr       string
+	}{
+		{goPath: "ID", wantName: "id", wantType: "string"},
+		{goPath: "Name", wantName: "name", wantType: "string"},
+		{goPath: "Brand", wantName: "brand", wantType: "string", wantFacetable: true},
+		{goPath: "Price", wantName: "price", wantType: "float", wantSortable: true},
+		{goPath: "Rank", wantName: "rank", wantType: "int64", wantSortable: true},
+		{goPath: "SKU", wantName: "sku", wantType: "string"},
+		{goPath: "Address", wantName: "address", wantType: "object"},
+		{goPath: "Address.City", wantName: "address.city", wantType: "string", wantFacetable: true},
+		{goPath: "Address.Lat", wantName: "address.lat", wantType: "float", wantSortable: true},
+		// untagged nested fields are flattened by typesense , not declared
+		{goPath: "Address.Zip", wantName: "address.zip", wantType: "string"},
+		{goPath: "Branches.City", wantName: "branches.city", wantType: "string[]", wantFacetable: true},
+		{goPath: "Branches.Lat", wantName: "branches.lat", wantType: "float[]", wantSortable: true},
+		{goPath: "Secret", wantErr: `not part of the schema (json:"-")`},
+		{goPath: "internal", wantErr: "has no exported field internal"},
+		{goPath: "Missing", wantErr: "has no exported field Missing"},
+		{goPath: "Address.Missing", wantErr: "has no exported field Missing"},
+		{goPath: "Name.First", wantErr: "string is not a struct"},
+		{goPath: "Meta.Key", wantErr: "is not a struct"},
+		{goPath: "", wantErr: "has no exported field"},
+	}
+	for _, test := range tests {
+		t.Run(test.goPath, func(t *testing.T) {
+			ref := Field[fieldRefTestProduct](test.goPath)
+			name, err := ref.FieldName()
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("err = %v , want %q", err, test.wantErr)
+				}
+				if _, err := ref.Sortable(); err == nil {
+					t.Fatal("Sortable() of an invalid field should error")
+				}
+				if _, err := ref.Facetable(); err == nil {
+					t.Fatal("Facetable() of an invalid field should error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			schema, _ := ref.Schema()
+			if name != test.wantName || schema.Type != test.wantType {
+				t.Fatalf("name = %q , type = %q", name, schema.Type)
+			}
+			sortName, err := ref.Sortable()
+			if (err == nil) != test.wantSortable || (err == nil && sortName != test.wantName) {
+				t.Fatalf("Sortable() = %q , %v", sortName, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), TagSort) {
+				t.Fatalf("Sortable() err = %v , want the tag hint", err)
+			}
+			facetName, err := ref.Facetable()
+			if (err == nil) != test.wantFacetable || (err == nil && facetName != test.wantName) {
+				t.Fatalf("Facetable() = %q , %v", facetName, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), TagFacet) {
+				t.Fatalf("Facetable() err = %v , want the tag hint", err)
+			}
+		})
+	}
+}
+
+func TestFieldSearchParams(t *testing.T) {
+	field := Field[fieldRefTestProduct]
+	tests := []struct {
+		name      string
+		params    *SearchParameters
+		wantQuery map[string]string
+		wantErr   string
+	}{
+		{
+			name:      "query by",
+			params:    NewSearchParams().AddQueryByFields(field("Name"), field("SKU"), field("Address.Zip")),
+			wantQuery: map[string]string{"query_by": "name,sku,address.zip"},
+		},
+		{
+			name:      "facet by",
+			params:    NewSearchParams().AddFacetByFields(field("Brand"), field("Address.City")),
+			wantQuery: map[string]string{"facet_by": "brand,address.city"},
+		},
+		{
+			name: "sort by appended",
+			params: NewSearchParams().
+				AddSortByField(field("Price"), SortDesc).
+				AddSortByField(field("Rank"), SortAsc).
+				AddSortByField(field("Address.Lat"), SortAsc),
+			wantQuery: map[string]string{"sort_by": "price:desc,rank:asc,address.lat:asc"},
+		},
+		{
+			name:      "filter by",
+			params:    NewSearchParams().AddFilter(filter.Ref(field("Address.City")).Exact("Paris")),
+			wantQuery: map[string]string{"filter_by": "address.city:=`Paris`"},
+		},
+		{name: "query by missing field", params: NewSearchParams().AddQueryByFields(field("Name"), field("Missing")), wantErr: "no exported field Missing"},
+		{name: "facet by untagged field", params: NewSearchParams().AddFacetByFields(field("Name")), wantErr: "name) is not facetable"},
+		{name: "facet by untagged nested field", params: NewSearchParams().AddFacetByFields(field("Address.Zip")), wantErr: "address.zip) is not facetable"},
+		{name: "sort by untagged field", params: NewSearchParams().AddSortByField(field("Brand"), SortAsc), wantErr: "brand) is not sortable"},
+		{name: "sort by untagged nested field", params: NewSearchParams().AddSortByField(field("Address.City"), SortAsc), wantErr: "address.city) is not sortable"},
+		{name: "unknown sort order", params: NewSearchParams().AddSortByField(field("Price"), "up"), wantErr: "unknown sort order"},
+		{name: "nil reference", params: NewSearchParams().AddQueryByFields(nil), wantErr: "cannot be nil"},
+		{name: "filter by missing field", params: NewSearchParams().AddFilter(filter.Ref(field("Missing")).Exact("x")), wantErr: "no exported field Missing"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, err := toQueryParams(test.params)
+			if err == nil {
+				err = test.params.err
+			}
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("err = %v , want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range test.wantQuery {
+				if query[key] != want {
+					t.Fatalf("%s = %q , want %q", key, query[key], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baderkha/typesense/types"
+)
+
+// FieldBuilder : builds conditions on a single field , start one with Field
+type FieldBuilder struct {
+	name string
+	err  error
+}
+
+// Field : conditions on the typesense field name (use dots for nested fields , ie address.city)
+func Field(name string) FieldBuilder {
+	if name == "" {
+		return FieldBuilder{err: fmt.Errorf("filter : field name cannot be empty")}
+	}
+	return FieldBuilder{name: name}
+}
+
+// FieldNamer : a field reference that resolves to its typesense name (ie typesense.Field[Model]("GoField"))
+type FieldNamer interface {
+	FieldName() (string, error)
+}
+
+// Ref : conditions on a referenced field , conditions fail to build if the reference doesn't resolve
+//
+// Example :
+//			filter.Ref(typesense.Field[Product]("Brand")).Exact("Nike")
+func Ref(ref FieldNamer) FieldBuilder {
+	if ref == nil {
+		return FieldBuilder{err: fmt.Errorf("filter : field reference cannot be nil")}
+	}
+	name, err := ref.FieldName()
+	if err != nil {
+		return FieldBuilder{err: err}
+	}
+	return Field(name)
+}
+
+// Eq : field:value , for string fields this matches on the tokens of the value (use Exact for the whole value)
+func (f FieldBuilder) Eq(value any) Expr {
+	return f.condition("", value)
+}
+
+// Exact : field:=value
+func (f FieldBuilder) Exact(value any) Expr {
+	return f.condition("=", value)
+}
+
+// NotEq : field:!=value
+func (f FieldBuilder) NotEq(value any) Expr {
+	return f.condition("!=", value)
+}
+
+// Gt : field:>value
+func (f FieldBuilder) Gt(value any) Expr {
+	return f.condition(">", value)
+}
+
+// Gte : field:>=value
+func (f FieldBuilder) Gte(value any) Expr {
+	return f.condition(">=", value)
+}
+
+// Lt : field:<value
+func (f FieldBuilder) Lt(value any) Expr {
+	return f.condition("<", value)
+}
+
+// Lte : field:<=value
+func (f FieldBuilder) Lte(value any) Expr {
+	return f.condition("<=", value)
+}
+
+// Range : field:[min..max] (inclusive)
+func (f FieldBuilder) Range(min any, max any) Expr {
+	if f.err != nil {
+		return errExpr{f.err}
+	}
+	minVal, err := FormatValue(min)
+	if err != nil {
+		return errExpr{f.valueErr(err)}
+	}
+	maxVal, err := FormatValue(max)
+	if err != nil {
+		return errExpr{f.valueErr(err)}
+	}
+	return condition{field: f.name, value: fmt.Sprintf("[%s..%s]", minVal, maxVal)}
+}
+
+// In : field:[a, b, c] , matches any of the values
+func (f FieldBuilder) In(values ...any) Expr {
+	return f.list("", values)
+}
+
+// ExactIn : field:=[a, b, c] , matches any of the values exactly
+func (f FieldBuilder) ExactIn(values ...any) Expr {
+	return f.list("=", values)
+}
+
+// NotIn : field:!=[a, b, c] , matches none of the values
+func (f FieldBuilder) NotIn(values ...any) Expr {
+	return f.list("!=", values)
+}
+
+// WithinRadius : the geopoint field is within the radius of the center , unit is km or mi
+//
+// Example :
+//			// location:(48.85, 2.29, 5.1 km)
+//			filter.Field("location").WithinRadius(types.GeoPoint{Lat: 48.85, Lng: 2.29}, 5.1, "km")
+func (f FieldBuilder) WithinRadius(center types.GeoPoint, radius float64, unit string) Expr {
+	if f.err != nil {
+		return errExpr{f.err}
+	}
+	if unit != "km" && unit != "mi" {
+		return errExpr{fmt.Errorf("filter : unknown geo unit '%s' for %s field , use km or mi", unit, f.name)}
+	}
+	return condition{field: f.name, value: fmt.Sprintf(
+		"(%s, %s, %s %s)",
+		formatFloat(center.Lat),
+		formatFloat(center.Lng),
+		formatFloat(radius),
+		unit,
+	)}
+}
+
+// WithinPolygon : the geopoint field is inside the polygon (at least 3 points)
+//
+// Example :
+//			// location:(48.8, 2.3, 48.9, 2.3, 48.9, 2.4)
+//			filter.Field("location").WithinPolygon(types.GeoPoint{Lat: 48.8, Lng: 2.3}, types.GeoPoint{Lat: 48.9, Lng: 2.3}, types.GeoPoint{Lat: 48.9, Lng: 2.4})
+func (f FieldBuilder) WithinPolygon(polygon ...types.GeoPoint) Expr {
+	if f.err != nil {
+		return errExpr{f.err}
+	}
+	if len(polygon) < 3 {
+		return errExpr{fmt.Errorf("filter : polygon on %s field needs at least 3 points , got %d", f.name, len(polygon))}
+	}
+	var coordinates []string
+	for _, point := range polygon {
+		coordinates = append(coordinates, formatFloat(point.Lat), formatFloat(point.Lng))
+	}
+	return condition{field: f.name, value: fmt.Sprintf("(%s)", strings.Join(coordinates, ", "))}
+}
+
+func (f FieldBuilder) condition(op string, value any) Expr {
+	if f.err != nil {
+		return errExpr{f.err}
+	}
+	formatted, err := FormatValue(value)
+	if err != nil {
+		return errExpr{f.valueErr(err)}
+	}
+	return condition{field: f.name, op: op, value: formatted}
+}
+
+func (f FieldBuilder) list(op string, values []any) Expr {
+	if f.err != nil {
+		return errExpr{f.err}
+	}
+	if len(values) == 0 {
+		return errExpr{fmt.Errorf("filter : %s field needs at least one value to match against", f.name)}
+	}
+	formatted := make([]string, 0, len(values))
+	for _, value := range values {
+		v, err := FormatValue(value)
+		if err != nil {
+			return errExpr{f.valueErr(err)}
+		}
+		formatted = append(formatted, v)
+	}
+	return condition{field: f.name, op: op, value: fmt.Sprintf("[%s]", strings.Join(formatted, ", "))}
+}
+
+func (f FieldBuilder) valueErr(err error) error {
+	return fmt.Errorf("%s for %s field", err.Error(), f.name)
+}
+
+// FormatValue : a go value as a filter_by value . strings are always quoted with backticks (so commas , brackets ..etc
+// are kept as part of the value) , times are sent as unix seconds like the int64 fields they map to
+func FormatValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return Quote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return formatFloat(v), nil
+	case time.Time:
+		return strconv.FormatInt(v.Unix(), 10), nil
+	case types.Timestamp:
+		return strconv.FormatInt(time.Time(v).Unix(), 10), nil
+	}
+
+	// named types (ie enums) , pointers
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return Quote(rv.String()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return formatFloat(rv.Float()), nil
+	case reflect.Ptr:
+		if !rv.IsNil() {
+			return FormatValue(rv.Elem().Interface())
+		}
+	}
+	return "", fmt.Errorf("filter : unsupported value %v (%T)", value, value)
+}
+
+// Quote : wraps a string value in backticks , backticks inside the value are escaped
+func Quote(value string) string {
+	return "`" + strings.ReplaceAll(value, "`", "\\`") + "`"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,186 @@
+// Package filter : builds typesense filter_by expressions without hand writing (and hand escaping) the syntax
+//
+// see https://typesense.org/docs/latest/api/search.html#filter-parameters
+//
+// Example :
+//			// (brand:=`Nike` || brand:=`Adidas`) && price:[10..100] && in_stock:true
+//			expr := filter.And(
+//				filter.Or(
+//					filter.Field("brand").Exact("Nike"),
+//					filter.Field("brand").Exact("Adidas"),
+//				),
+//				filter.Field("price").Range(10, 100),
+//				filter.Field("in_stock").Eq(true),
+//			)
+//			params := typesense.NewSearchParams().AddFilter(expr)
+//
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	opAnd = "&&"
+	opOr  = "||"
+)
+
+// Expr : a filter_by expression
+type Expr interface {
+	// Build : the filter_by string , errors if the expression is invalid (ie an In without values)
+	Build() (string, error)
+}
+
+// Raw : an already written filter_by expression , used as is
+func Raw(filterBy string) Expr {
+	return rawExpr(filterBy)
+}
+
+type rawExpr string
+
+func (r rawExpr) Build() (string, error) {
+	return string(r), nil
+}
+
+// errExpr : an expression that failed while being built , the error is returned by Build
+type errExpr struct {
+	err error
+}
+
+func (e errExpr) Build() (string, error) {
+	return "", e.err
+}
+
+// condition : a single field condition (ie price:>10)
+type condition struct {
+	field string
+	op    string
+	value string
+}
+
+func (c condition) Build() (string, error) {
+	return c.field + ":" + c.op + c.value, nil
+}
+
+// group : expressions joined by && or ||
+type group struct {
+	op    string
+	exprs []Expr
+}
+
+// And : all the expressions must match , nil expressions are skipped
+func And(exprs ...Expr) Expr {
+	return group{op: opAnd, exprs: exprs}
+}
+
+// Or : any of the expressions must match , nil expressions are skipped
+func Or(exprs ...Expr) Expr {
+	return group{op: opOr, exprs: exprs}
+}
+
+func (g group) Build() (string, error) {
+	var parts []string
+	for _, expr := range g.exprs {
+		if expr == nil {
+			continue
+		}
+		part, err := expr.Build()
+		if err != nil {
+			return "", err
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) > 1 {
+		for i, part := range parts {
+			if g.needsParens(part) {
+				parts[i] = "(" + part + ")"
+			}
+		}
+	}
+	return strings.Join(parts, " "+g.op+" "), nil
+}
+
+// needsParens : parts with a top level operator other than the group's are wrapped so the precedence is kept
+// (decided on the built string , so nested single expression groups and raw expressions are handled the same way)
+func (g group) needsParens(part string) bool {
+	hasAnd, hasOr := topLevelOps(part)
+	if g.op == opAnd {
+		return hasOr
+	}
+	return hasAnd
+}
+
+// HasTopLevelOr : checks a filter_by string for an || that isn't wrapped in parentheses (or a backtick quoted value) ,
+// such a filter has to be wrapped before it's joined to another one with &&
+func HasTopLevelOr(filterBy string) bool {
+	_, hasOr := topLevelOps(filterBy)
+	return hasOr
+}
+
+// topLevelOps : checks for && / || that aren't wrapped in parentheses (or a backtick quoted value)
+func topLevelOps(filterBy string) (hasAnd bool, hasOr bool) {
+	var depth int
+	var quoted bool
+	for i := 0; i < len(filterBy); i++ {
+		switch {
+		case filterBy[i] == '`':
+			quoted = !quoted
+		case quoted && filterBy[i] == '\\':
+			// escaped backtick inside a value
+			i++
+		case quoted:
+		case filterBy[i] == '(':
+			depth++
+		case filterBy[i] == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(filterBy[i:], opAnd):
+			hasAnd = true
+			i++
+		case depth == 0 && strings.HasPrefix(filterBy[i:], opOr):
+			hasOr = true
+			i++
+		}
+	}
+	return hasAnd, hasOr
+}
+
+// Join : filters on a referenced collection (reference fields) , matches documents that have a reference
+// to a document of the collection matching the expression
+//
+// Example :
+//			// $customers(country:=`France`)
+//			filter.Join("customers", filter.Field("country").Exact("France"))
+func Join(collection string, expr Expr) Expr {
+	if collection == "" {
+		return errExpr{fmt.Errorf("filter : join needs a collection name")}
+	}
+	return joinExpr{collection: collection, expr: expr}
+}
+
+type joinExpr struct {
+	collection string
+	expr       Expr
+}
+
+func (j joinExpr) Build() (string, error) {
+	if j.expr == nil {
+		return "", fmt.Errorf("filter : join on %s needs an expression", j.collection)
+	}
+	inner, err := j.expr.Build()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("$%s(%s)", j.collection, inner), nil
+}
+
+// String : the filter_by string of an expression , empty if the expression is invalid
+func String(expr Expr) string {
+	if expr == nil {
+		return ""
+	}
+	filterBy, _ := expr.Build()
+	return filterBy
+}
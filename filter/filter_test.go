@@ -0,0 +1,216 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baderkha/typesense/types"
+)
+
+type status string
+
+type namer struct {
+	name string
+	err  error
+}
+
+func (n namer) FieldName() (string, error) {
+	return n.name, n.err
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "Nike", want: "`Nike`"},
+		{value: "", want: "``"},
+		{value: "a, b", want: "`a, b`"},
+		{value: "[x] && (y || z)", want: "`[x] && (y || z)`"},
+		{value: "it`s", want: "`it\\`s`"},
+		{value: "``", want: "`\\`\\``"},
+	}
+	for _, test := range tests {
+		if got := Quote(test.value); got != test.want {
+			t.Errorf("Quote(%q) = %s , want %s", test.value, got, test.want)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	ten := 10
+	var nilInt *int
+	at := time.Unix(1665360000, 0)
+
+	tests := []struct {
+		name    string
+		value   any
+		want    string
+		wantErr bool
+	}{
+		{name: "string", value: "Nike", want: "`Nike`"},
+		{name: "named string", value: status("active"), want: "`active`"},
+		{name: "bool", value: true, want: "true"},
+		{name: "int", value: -42, want: "-42"},
+		{name: "int64", value: int64(1) << 40, want: "1099511627776"},
+		{name: "uint8", value: uint8(7), want: "7"},
+		{name: "float32", value: float32(1.5), want: "1.5"},
+		{name: "float64", value: 0.1, want: "0.1"},
+		{name: "float64 no exponent", value: 1e21, want: "1000000000000000000000"},
+		{name: "time", value: at, want: "1665360000"},
+		{name: "timestamp", value: types.Timestamp(at), want: "1665360000"},
+		{name: "pointer", value: &ten, want: "10"},
+		{name: "nil pointer", value: nilInt, wantErr: true},
+		{name: "nil", value: nil, wantErr: true},
+		{name: "slice", value: []int{1}, wantErr: true},
+		{name: "struct", value: struct{}{}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := FormatValue(test.value)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("FormatValue(%v) error = %v , wantErr %v", test.value, err, test.wantErr)
+			}
+			if got != test.want {
+				t.Fatalf("FormatValue(%v) = %s , want %s", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	a := Field("a").Eq(1)
+	b := Field("b").Eq(2)
+	c := Field("c").Eq(3)
+
+	tests := []struct {
+		name    string
+		expr    Expr
+		want    string
+		wantErr bool
+	}{
+		// conditions
+		{name: "eq", expr: Field("brand").Eq("Nike"), want: "brand:`Nike`"},
+		{name: "exact", expr: Field("brand").Exact("Nike"), want: "brand:=`Nike`"},
+		{name: "not eq", expr: Field("brand").NotEq("Nike"), want: "brand:!=`Nike`"},
+		{name: "gt", expr: Field("price").Gt(10), want: "price:>10"},
+		{name: "gte", expr: Field("price").Gte(10.5), want: "price:>=10.5"},
+		{name: "lt", expr: Field("price").Lt(10), want: "price:<10"},
+		{name: "lte", expr: Field("price").Lte(10), want: "price:<=10"},
+		{name: "nested field", expr: Field("address.city").Exact("Paris"), want: "address.city:=`Paris`"},
+		{name: "empty field", expr: Field("").Eq(1), wantErr: true},
+		{name: "unsupported value", expr: Field("a").Eq([]int{1}), wantErr: true},
+
+		// ranges and lists
+		{name: "range", expr: Field("price").Range(10, 100), want: "price:[10..100]"},
+		{name: "range floats", expr: Field("rating").Range(0.5, 4.5), want: "rating:[0.5..4.5]"},
+		{name: "range bad value", expr: Field("price").Range(10, struct{}{}), wantErr: true},
+		{name: "in", expr: Field("brand").In("Nike", "Adidas"), want: "brand:[`Nike`, `Adidas`]"},
+		{name: "in value with comma", expr: Field("brand").In("a, b"), want: "brand:[`a, b`]"},
+		{name: "exact in", expr: Field("id").ExactIn(1, 2, 3), want: "id:=[1, 2, 3]"},
+		{name: "not in", expr: Field("brand").NotIn("Nike"), want: "brand:!=[`Nike`]"},
+		{name: "in without values", expr: Field("brand").In(), wantErr: true},
+		{name: "not in bad value", expr: Field("brand").NotIn("a", nil), wantErr: true},
+
+		// geo
+		{
+			name: "within radius",
+			expr: Field("location").WithinRadius(types.GeoPoint{Lat: 48.85, Lng: 2.29}, 5.1, "km"),
+			want: "location:(48.85, 2.29, 5.1 km)",
+		},
+		{name: "within radius bad unit", expr: Field("location").WithinRadius(types.GeoPoint{}, 1, "m"), wantErr: true},
+		{
+			name: "within polygon",
+			expr: Field("location").WithinPolygon(
+				types.GeoPoint{Lat: 48.8, Lng: 2.3},
+				types.GeoPoint{Lat: 48.9, Lng: 2.3},
+				types.GeoPoint{Lat: 48.9, Lng: 2.4},
+			),
+			want: "location:(48.8, 2.3, 48.9, 2.3, 48.9, 2.4)",
+		},
+		{name: "within polygon too few points", expr: Field("location").WithinPolygon(types.GeoPoint{}, types.GeoPoint{}), wantErr: true},
+
+		// references
+		{name: "ref", expr: Ref(namer{name: "brand"}).Exact("Nike"), want: "brand:=`Nike`"},
+		{name: "ref error", expr: Ref(namer{err: errTest}).Exact("Nike"), wantErr: true},
+		{name: "nil ref", expr: Ref(nil).Exact("Nike"), wantErr: true},
+
+		// groups
+		{name: "and", expr: And(a, b), want: "a:1 && b:2"},
+		{name: "or", expr: Or(a, b), want: "a:1 || b:2"},
+		{name: "empty group", expr: And(), want: ""},
+		{name: "nil skipped", expr: And(nil, a, nil), want: "a:1"},
+		{name: "empty parts skipped", expr: And(Raw(""), a, Or()), want: "a:1"},
+		{name: "or in and", expr: And(a, Or(b, c)), want: "a:1 && (b:2 || c:3)"},
+		{name: "and in or", expr: Or(a, And(b, c)), want: "a:1 || (b:2 && c:3)"},
+		{name: "same operator not wrapped", expr: And(a, And(b, c)), want: "a:1 && b:2 && c:3"},
+		{name: "single or in and", expr: And(Or(a, b)), want: "a:1 || b:2"},
+		{name: "single group wrapping a group", expr: And(a, Or(Or(b, c))), want: "a:1 && (b:2 || c:3)"},
+		{name: "single and wrapping an or", expr: And(a, And(Or(b, c))), want: "a:1 && (b:2 || c:3)"},
+		{name: "deep nesting", expr: Or(And(a, Or(b, c)), c), want: "(a:1 && (b:2 || c:3)) || c:3"},
+		{name: "raw or in and", expr: And(a, Raw("b:2 || c:3")), want: "a:1 && (b:2 || c:3)"},
+		{name: "raw without operator", expr: And(a, Raw("b:2")), want: "a:1 && b:2"},
+		{name: "raw already wrapped", expr: And(a, Raw("(b:2 || c:3)")), want: "a:1 && (b:2 || c:3)"},
+		{name: "operator inside value", expr: And(a, Field("b").Exact("x || y")), want: "a:1 && b:=`x || y`"},
+		{name: "error in group", expr: And(a, Field("b").In()), wantErr: true},
+
+		// joins
+		{name: "join", expr: Join("customers", Field("country").Exact("France")), want: "$customers(country:=`France`)"},
+		{name: "join in and", expr: And(a, Join("customers", Or(b, c))), want: "a:1 && $customers(b:2 || c:3)"},
+		{name: "join without collection", expr: Join("", a), wantErr: true},
+		{name: "join without expression", expr: Join("customers", nil), wantErr: true},
+		{name: "join error", expr: Join("customers", Field("").Eq(1)), wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.expr.Build()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Build() error = %v , wantErr %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Fatalf("Build() = %s , want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestHasTopLevelOr(t *testing.T) {
+	tests := []struct {
+		filterBy string
+		want     bool
+	}{
+		{filterBy: "", want: false},
+		{filterBy: "a:1 && b:2", want: false},
+		{filterBy: "a:1 || b:2", want: true},
+		{filterBy: "(a:1 || b:2) && c:3", want: false},
+		{filterBy: "a:=`x || y`", want: false},
+		{filterBy: "a:=`x\\` || y`", want: false},
+		{filterBy: "a:=`x\\`` || b:2", want: true},
+		{filterBy: "$customers(a:1 || b:2)", want: false},
+	}
+	for _, test := range tests {
+		if got := HasTopLevelOr(test.filterBy); got != test.want {
+			t.Errorf("HasTopLevelOr(%s) = %v , want %v", test.filterBy, got, test.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	if got := String(nil); got != "" {
+		t.Fatalf("String(nil) = %s", got)
+	}
+	if got := String(Field("").Eq(1)); got != "" {
+		t.Fatalf("String(invalid) = %s", got)
+	}
+	if got := String(Field("a").Eq(1)); got != "a:1" {
+		t.Fatalf("String() = %s", got)
+	}
+}
+
+var errTest = testError("test error")
+
+type testError string
+
+func (e testError) Error() string {
+	return string(e)
+}
@@ -0,0 +1,174 @@
+package typesense
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	http2 "github.com/baderkha/typesense/pkg/http"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxImportErrorIDs : failed ids listed in the ImportError message (all of them are in ImportError.FailedIDs)
+	maxImportErrorIDs = 10
+)
+
+// ImportResult : the result of one imported line , results are in the same order as the imported lines
+type ImportResult struct {
+	Success bool `json:"success"`
+	// Error : why the line was rejected
+	Error string `json:"error,omitempty"`
+	// Document : the rejected line as it was sent
+	Document string `json:"document,omitempty"`
+	Code     int    `json:"code,omitempty"`
+	// ID : the id of the document (read from the rejected line when typesense does not return it)
+	ID string `json:"id,omitempty"`
+}
+
+// ImportError : some of the imported lines were rejected , the rest were imported
+type ImportError struct {
+	Collection string
+	// Total : number of imported lines
+	Total int
+	// Failed : the results of the rejected lines
+	Failed []ImportResult
+	// FailedIDs : the ids of the rejected documents (documents without an id are left out)
+	FailedIDs []string
+}
+
+func (e *ImportError) Error() string {
+	ids := e.FailedIDs
+	more := ""
+	if len(ids) > maxImportErrorIDs {
+		more = fmt.Sprintf(" and %d more", len(ids)-maxImportErrorIDs)
+		ids = ids[:maxImportErrorIDs]
+	}
+	return fmt.Sprintf(
+		"Typesense : %d of %d documents failed to import into %s (ids : %s%s) , first error : %s",
+		len(e.Failed),
+		e.Total,
+		e.Collection,
+		strings.Join(ids, ", "),
+		more,
+		e.Failed[0].Error,
+	)
+}
+
+// newImportError : an *ImportError if any of the results failed , nil otherwise
+func newImportError(colName string, results []ImportResult) error {
+	importErr := ImportError{
+		Collection: colName,
+		Total:      len(results),
+	}
+	for _, result := range results {
+		if result.Success {
+			continue
+		}
+		importErr.Failed = append(importErr.Failed, result)
+		if result.ID != "" {
+			importErr.FailedIDs = append(importErr.FailedIDs, result.ID)
+		}
+	}
+	if len(importErr.Failed) == 0 {
+		return nil
+	}
+	return &importErr
+}
+
+// FailedDocuments : decodes the rejected lines of an import back to the model so they can be fixed and retried
+func FailedDocuments[T any](results []ImportResult) ([]*T, error) {
+	var failed []*T
+	for _, result := range results {
+		if result.Success {
+			continue
+		}
+		var doc T
+		err := json.Unmarshal([]byte(result.Document), &doc)
+		if err != nil {
+			return failed, errors.Wrap(err, "Typesense : could not decode a failed document")
+		}
+		failed = append(failed, &doc)
+	}
+	return failed, nil
+}
+
+// importWithResults : imports jsonl into a collection and parses the result of every line ,
+// errors with an *ImportError if any of the lines were rejected
+func (m *baseClient[T]) importWithResults(colName string, params map[string]string, jsonLines []byte) ([]ImportResult, error) {
+	res, err := m.Req().
+		SetHeader("Content-Type", "text/plain").
+		SetBody(jsonLines).
+		SetQueryParams(params).
+		SetQueryParam("return_id", "true").
+		Post(fmt.Sprintf("/collections/%s/documents/import", colName))
+	if err != nil {
+		return nil, err
+	} else if !http2.StatusIsSuccess(res.StatusCode()) {
+		return nil, &importStatusError{
+			statusCode: res.StatusCode(),
+			err:        typesenseToError(res.Body(), res.StatusCode()),
+		}
+	}
+
+	results, err := parseImportResults(res.Body())
+	if err != nil {
+		return results, err
+	}
+	return results, newImportError(colName, results)
+}
+
+// importStatusError : typesense answered the whole import with an error status (ie missing collection)
+type importStatusError struct {
+	statusCode int
+	err        error
+}
+
+func (e *importStatusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *importStatusError) Unwrap() error {
+	return e.err
+}
+
+// isRetryableImportError : the import could not reach typesense or typesense failed / was overloaded ,
+// sending the same import again may work . rejected documents and client errors (4xx) won't
+func isRetryableImportError(err error) bool {
+	var rejected *ImportError
+	if err == nil || errors.As(err, &rejected) {
+		return false
+	}
+	var statusErr *importStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode >= http.StatusInternalServerError || statusErr.statusCode == http.StatusTooManyRequests
+	}
+	// connection errors ..etc
+	return true
+}
+
+// parseImportResults : one json result per line
+func parseImportResults(body []byte) ([]ImportResult, error) {
+	var results []ImportResult
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var result ImportResult
+		err := json.Unmarshal(line, &result)
+		if err != nil {
+			return results, errors.Wrap(err, "Typesense : could not read the import results")
+		}
+		if result.ID == "" && result.Document != "" {
+			var doc struct {
+				ID string `json:"id"`
+			}
+			_ = json.Unmarshal([]byte(result.Document), &doc)
+			result.ID = doc.ID
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,228 @@
+package typesense
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultImportChunkLines = 10000
+	defaultImportChunkBytes = 10 << 20 // 10MB
+)
+
+// ImportStreamOptions : how ImportStream splits the jsonl into chunks and uploads them , nil uses the defaults
+type ImportStreamOptions struct {
+	// ChunkLines : max lines per uploaded chunk (default 10000)
+	ChunkLines int
+	// ChunkBytes : max bytes per uploaded chunk (default 10MB) , a single line bigger than this is sent on its own
+	ChunkBytes int
+	// Concurrency : number of chunks uploaded at the same time (default 1 , one after another)
+	Concurrency int
+	// OnlyFailed : only keep the results of the rejected lines (saves memory on big imports)
+	OnlyFailed bool
+	// Progress : called after every uploaded chunk (never concurrently)
+	Progress func(ImportProgress)
+}
+
+// ImportProgress : how far along a streaming import is
+type ImportProgress struct {
+	// Chunks : uploaded chunks
+	Chunks int
+	// Lines : uploaded lines
+	Lines int
+	// Bytes : uploaded bytes
+	Bytes int64
+	// Failed : lines typesense rejected so far
+	Failed int
+}
+
+func (o *ImportStreamOptions) withDefaults() ImportStreamOptions {
+	var opts ImportStreamOptions
+	if o != nil {
+		opts = *o
+	}
+	if opts.ChunkLines <= 0 {
+		opts.ChunkLines = defaultImportChunkLines
+	}
+	if opts.ChunkBytes <= 0 {
+		opts.ChunkBytes = defaultImportChunkBytes
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	return opts
+}
+
+type importChunk struct {
+	index int
+	lines int
+	body  []byte
+}
+
+type importChunkResult struct {
+	chunk   importChunk
+	results []ImportResult
+	err     error
+}
+
+func (d *DocumentClient[T]) ImportStream(r io.Reader, action string, opts *ImportStreamOptions) ([]ImportResult, error) {
+	o := opts.withDefaults()
+	colName := d.resolveColName()
+	params := d.importParams(action)
+
+	chunks := make(chan importChunk)
+	chunkResults := make(chan importChunkResult)
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+	stopAll := func() { stopOnce.Do(func() { close(stop) }) }
+
+	var readErr error
+	go func() {
+		defer close(chunks)
+		readErr = splitJSONLines(r, o.ChunkLines, o.ChunkBytes, chunks, stop)
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < o.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				results, err := d.importWithResults(colName, params, chunk.body)
+				chunkResults <- importChunkResult{chunk: chunk, results: results, err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(chunkResults)
+	}()
+
+	var progress ImportProgress
+	var importErr error
+	resultsByChunk := make(map[int][]ImportResult)
+	for chunkRes := range chunkResults {
+		var rejected *ImportError
+		if chunkRes.err != nil && !errors.As(chunkRes.err, &rejected) {
+			// the chunk could not be imported at all , don't send the rest
+			if importErr == nil {
+				importErr = chunkRes.err
+			}
+			stopAll()
+			continue
+		}
+		results := chunkRes.results
+		if o.OnlyFailed {
+			results = failedResults(results)
+		}
+		resultsByChunk[chunkRes.chunk.index] = results
+
+		progress.Chunks++
+		progress.Lines += chunkRes.chunk.lines
+		progress.Bytes += int64(len(chunkRes.chunk.body))
+		if rejected != nil {
+			progress.Failed += len(rejected.Failed)
+		}
+		if o.Progress != nil {
+			o.Progress(progress)
+		}
+	}
+
+	results := mergeChunkResults(resultsByChunk)
+	if importErr != nil {
+		return results, importErr
+	}
+	if readErr != nil {
+		return results, errors.Wrap(readErr, typesenseErrPrefix)
+	}
+	if err := newImportError(colName, results); err != nil {
+		// the total is the number of lines even when only the failed results were kept
+		err.(*ImportError).Total = progress.Lines
+		return results, err
+	}
+	return results, nil
+}
+
+func (d *DocumentClient[T]) ImportStreamFromFile(path string, action string, opts *ImportStreamOptions) ([]ImportResult, error) {
+	file, err := fs.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, typesenseErrPrefix)
+	}
+	defer file.Close()
+	return d.ImportStream(file, action, opts)
+}
+
+// splitJSONLines : reads the jsonl and sends it in chunks of at most maxLines lines / maxBytes bytes
+func splitJSONLines(r io.Reader, maxLines int, maxBytes int, chunks chan<- importChunk, stop <-chan struct{}) error {
+	reader := bufio.NewReader(r)
+	var buf bytes.Buffer
+	var lines, index int
+	send := func() bool {
+		if lines == 0 {
+			return true
+		}
+		chunk := importChunk{index: index, lines: lines, body: append([]byte(nil), buf.Bytes()...)}
+		select {
+		case chunks <- chunk:
+		case <-stop:
+			return false
+		}
+		index++
+		lines = 0
+		buf.Reset()
+		return true
+	}
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			if lines > 0 && buf.Len()+len(line)+1 > maxBytes && !send() {
+				return nil
+			}
+			if lines > 0 {
+				buf.WriteByte('\n')
+			}
+			buf.Write(line)
+			lines++
+			if lines >= maxLines && !send() {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			send()
+			return nil
+		}
+	}
+}
+
+func mergeChunkResults(resultsByChunk map[int][]ImportResult) []ImportResult {
+	indexes := make([]int, 0, len(resultsByChunk))
+	for index := range resultsByChunk {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	var results []ImportResult
+	for _, index := range indexes {
+		results = append(results, resultsByChunk[index]...)
+	}
+	return results
+}
+
+func failedResults(results []ImportResult) []ImportResult {
+	var failed []ImportResult
+	for _, result := range results {
+		if !result.Success {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
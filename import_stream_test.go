@@ -0,0 +1,323 @@
+package typesense
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type importTestDoc struct {
+	ID   string `json:"id"`
+	Bad  bool   `json:"bad,omitempty"`
+	Slow bool   `json:"slow,omitempty"`
+}
+
+// importTestServer : answers imports line by line , rejects documents with bad set and delays chunks with a slow document
+type importTestServer struct {
+	*httptest.Server
+	imports  int64
+	mu       sync.Mutex
+	received []string
+
+	// aliasTo : aliases point to this collection when set (before the first request)
+	aliasTo string
+	// failures : the next imports are answered with these statuses
+	failures []int
+}
+
+func newImportTestServer(t *testing.T) *importTestServer {
+	s := &importTestServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/aliases/") {
+			if s.aliasTo != "" {
+				_ = json.NewEncoder(w).Encode(Alias{Name: strings.TrimPrefix(r.URL.Path, "/aliases/"), CollectionName: s.aliasTo})
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/documents/import") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if s.aliasTo != "" && r.URL.Path != "/collections/"+s.aliasTo+"/documents/import" {
+			t.Errorf("import into %s , want the aliased collection %s", r.URL.Path, s.aliasTo)
+		}
+		atomic.AddInt64(&s.imports, 1)
+		s.mu.Lock()
+		if len(s.failures) > 0 {
+			status := s.failures[0]
+			s.failures = s.failures[1:]
+			s.mu.Unlock()
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"message":"failed"}`))
+			return
+		}
+		s.mu.Unlock()
+		var results []string
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			var doc importTestDoc
+			_ = json.Unmarshal([]byte(line), &doc)
+			if doc.Slow {
+				time.Sleep(50 * time.Millisecond)
+			}
+			s.mu.Lock()
+			s.received = append(s.received, doc.ID)
+			s.mu.Unlock()
+			if doc.Bad {
+				result, _ := json.Marshal(ImportResult{Error: "bad document", Document: line, Code: 400})
+				results = append(results, string(result))
+				continue
+			}
+			results = append(results, fmt.Sprintf(`{"success":true,"id":%q}`, doc.ID))
+		}
+		_, _ = w.Write([]byte(strings.Join(results, "\n")))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *importTestServer) failNext(statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statuses...)
+}
+
+func (s *importTestServer) receivedIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.received...)
+}
+
+func importTestJSONL(docs ...importTestDoc) string {
+	var b strings.Builder
+	for _, doc := range docs {
+		line, _ := json.Marshal(doc)
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestSplitJSONLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		maxLines int
+		maxBytes int
+		want     []string
+	}{
+		{name: "empty", input: "", maxLines: 2, maxBytes: 100},
+		{name: "blank lines", input: "\n \n\r\n", maxLines: 2, maxBytes: 100},
+		{name: "single chunk", input: "a\nb\nc\n", maxLines: 10, maxBytes: 100, want: []string{"a\nb\nc"}},
+		{name: "no trailing newline", input: "a\nb", maxLines: 10, maxBytes: 100, want: []string{"a\nb"}},
+		{name: "blank lines skipped", input: "a\n\n  b  \r\n\nc", maxLines: 10, maxBytes: 100, want: []string{"a\nb\nc"}},
+		{name: "by lines", input: "a\nb\nc\nd\ne\n", maxLines: 2, maxBytes: 100, want: []string{"a\nb", "c\nd", "e"}},
+		{name: "exact lines", input: "a\nb\nc\nd\n", maxLines: 2, maxBytes: 100, want: []string{"a\nb", "c\nd"}},
+		// "aa\nbb" is 5 bytes , adding "\ncc" would make it 8
+		{name: "by bytes", input: "aa\nbb\ncc\n", maxLines: 10, maxBytes: 5, want: []string{"aa\nbb", "cc"}},
+		{name: "bytes exactly full", input: "aa\nbb\ncc\n", maxLines: 10, maxBytes: 8, want: []string{"aa\nbb\ncc"}},
+		{name: "line bigger than chunk", input: "a\nbbbbbbbb\nc\n", maxLines: 10, maxBytes: 3, want: []string{"a", "bbbbbbbb", "c"}},
+		{name: "first line bigger than chunk", input: "bbbbbbbb\nc\n", maxLines: 10, maxBytes: 3, want: []string{"bbbbbbbb", "c"}},
+		{name: "one line per chunk", input: "a\nb\n", maxLines: 1, maxBytes: 100, want: []string{"a", "b"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chunks := make(chan importChunk)
+			var err error
+			go func() {
+				defer close(chunks)
+				err = splitJSONLines(strings.NewReader(test.input), test.maxLines, test.maxBytes, chunks, make(chan struct{}))
+			}()
+			var got []string
+			for chunk := range chunks {
+				if chunk.index != len(got) {
+					t.Fatalf("chunk index = %d , want %d", chunk.index, len(got))
+				}
+				if chunk.lines != strings.Count(string(chunk.body), "\n")+1 {
+					t.Fatalf("chunk lines = %d for %q", chunk.lines, chunk.body)
+				}
+				got = append(got, string(chunk.body))
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("chunks = %q , want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSplitJSONLinesStop(t *testing.T) {
+	chunks := make(chan importChunk)
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- splitJSONLines(strings.NewReader("a\nb\nc\n"), 1, 100, chunks, stop)
+	}()
+	<-chunks
+	close(stop)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("splitJSONLines did not stop")
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("disk on fire")
+}
+
+func TestSplitJSONLinesReadError(t *testing.T) {
+	chunks := make(chan importChunk, 1)
+	err := splitJSONLines(failingReader{}, 10, 100, chunks, make(chan struct{}))
+	if err == nil || err.Error() != "disk on fire" {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestMergeChunkResults(t *testing.T) {
+	results := mergeChunkResults(map[int][]ImportResult{
+		2: {{ID: "5"}},
+		0: {{ID: "1"}, {ID: "2"}},
+		1: {{ID: "3"}, {ID: "4"}},
+	})
+	var ids []string
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"1", "2", "3", "4", "5"}) {
+		t.Fatalf("ids = %v", ids)
+	}
+	if mergeChunkResults(map[int][]ImportResult{}) != nil {
+		t.Fatal("want no results")
+	}
+}
+
+func TestImportStream(t *testing.T) {
+	server := newImportTestServer(t)
+	client := NewDocumentClient[importTestDoc]("key", server.URL, false)
+
+	docs := []importTestDoc{
+		{ID: "1", Slow: true}, {ID: "2"},
+		{ID: "3", Bad: true}, {ID: "4"},
+		{ID: "5"}, {ID: "6", Bad: true},
+		{ID: "7"},
+	}
+	var progress []ImportProgress
+	results, err := client.ImportStream(strings.NewReader(importTestJSONL(docs...)), DocumentActionUpsert, &ImportStreamOptions{
+		ChunkLines:  2,
+		Concurrency: 3,
+		Progress: func(p ImportProgress) {
+			progress = append(progress, p)
+		},
+	})
+
+	var importErr *ImportError
+	if !errors.As(err, &importErr) {
+		t.Fatalf("err = %v , want an *ImportError", err)
+	}
+	if importErr.Total != 7 || !reflect.DeepEqual(importErr.FailedIDs, []string{"3", "6"}) {
+		t.Fatalf("import error = %+v", importErr)
+	}
+	// the first chunk is the slowest , results still come back in the order of the lines
+	var ids []string
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"1", "2", "3", "4", "5", "6", "7"}) {
+		t.Fatalf("result ids = %v", ids)
+	}
+	if got := atomic.LoadInt64(&server.imports); got != 4 {
+		t.Fatalf("imports = %d , want 4 chunks", got)
+	}
+	last := progress[len(progress)-1]
+	if len(progress) != 4 || last.Chunks != 4 || last.Lines != 7 || last.Failed != 2 {
+		t.Fatalf("progress = %+v", progress)
+	}
+	if received := server.receivedIDs(); received[0] == "1" {
+		t.Fatalf("received = %v , want the other chunks to be sent while the slow one is imported", received)
+	}
+}
+
+func TestImportStreamOnlyFailed(t *testing.T) {
+	server := newImportTestServer(t)
+	client := NewDocumentClient[importTestDoc]("key", server.URL, false)
+
+	input := importTestJSONL(importTestDoc{ID: "1"}, importTestDoc{ID: "2", Bad: true}, importTestDoc{ID: "3"})
+	results, err := client.ImportStream(strings.NewReader(input), DocumentActionCreate, &ImportStreamOptions{
+		ChunkLines: 1,
+		OnlyFailed: true,
+	})
+	var importErr *ImportError
+	if !errors.As(err, &importErr) || importErr.Total != 3 {
+		t.Fatalf("err = %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "2" {
+		t.Fatalf("results = %+v", results)
+	}
+	failed, err := FailedDocuments[importTestDoc](results)
+	if err != nil || len(failed) != 1 || failed[0].ID != "2" || !failed[0].Bad {
+		t.Fatalf("failed = %+v , err = %v", failed, err)
+	}
+}
+
+func TestImportStreamSuccess(t *testing.T) {
+	server := newImportTestServer(t)
+	client := NewDocumentClient[importTestDoc]("key", server.URL, false)
+
+	var input bytes.Buffer
+	for i := 0; i < 25; i++ {
+		input.WriteString(importTestJSONL(importTestDoc{ID: fmt.Sprint(i)}))
+	}
+	results, err := client.ImportStream(&input, DocumentActionUpsert, &ImportStreamOptions{ChunkBytes: 40, Concurrency: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 25 {
+		t.Fatalf("results = %d , want 25", len(results))
+	}
+	for i, result := range results {
+		if !result.Success || result.ID != fmt.Sprint(i) {
+			t.Fatalf("result %d = %+v", i, result)
+		}
+	}
+}
+
+func TestImportStreamRequestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer server.Close()
+	client := NewDocumentClient[importTestDoc]("key", server.URL, false)
+
+	input := importTestJSONL(importTestDoc{ID: "1"}, importTestDoc{ID: "2"}, importTestDoc{ID: "3"})
+	_, err := client.ImportStream(strings.NewReader(input), DocumentActionUpsert, &ImportStreamOptions{ChunkLines: 1, Concurrency: 2})
+	var importErr *ImportError
+	if err == nil || errors.As(err, &importErr) {
+		t.Fatalf("err = %v , want the request error", err)
+	}
+}
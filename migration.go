@@ -3,14 +3,8 @@ package typesense
 import (
 	"fmt"
 	"reflect"
-	"sort"
-	"strings"
-	"sync"
 
-	"github.com/baderkha/typesense/pkg/conditional"
 	http2 "github.com/baderkha/typesense/pkg/http"
-	"github.com/davecgh/go-spew/spew"
-	"github.com/tkrajina/go-reflector/reflector"
 )
 
 var _ IMigration[any] = &Migration[any]{}
@@ -36,6 +30,8 @@ type IMigration[T any] interface {
 	//			}
 	//
 	Auto() error
+	// AutoReindex : same as Reindex but the collection schema is built from the model
+	AutoReindex(transform ReindexTransform) error
 	// DeleteAliasCollection : deletes an alias pointer
 	DeleteAliasCollection(aliasName string) error
 	// DeleteCollection : deletes collection and collection data
@@ -46,11 +42,20 @@ type IMigration[T any] interface {
 	GetCollection(collection string) (doesExist bool, col Collection)
 	// GetCollectionFromAlias : get underlying collection for an alias name if the binding exists
 	GetCollectionFromAlias(aliasName string) (doesExist bool, col Collection)
+	// GetCollections : lists all the collections in typesense
+	GetCollections() ([]Collection, error)
+	// History : lists the recorded schema changes for an alias (or collection name when not aliased) newest first
+	//
+	// once turned on with SetMigrationHistory , every schema change applied through Manual / Auto / Reindex / Rollback
+	// is recorded in the MigrationHistoryCollection (the api key needs access to it) , see SetMigrationAppVersion
+	History(aliasName string) ([]MigrationRecord, error)
 	// Manual : if you don't trust auto migration , you can always migrate it yourself
 	// or build your own auto schema converter yourself .
 	//
 	// Note that the implementation uses aliasing .
 	//
+	// If the collection already exists , the field differences are applied in place (see DiffCollection)
+	//
 	// Example:
 	//			// Your Model you want to map to the client
 	//			type MyCoolModel struct {
@@ -69,6 +74,10 @@ type IMigration[T any] interface {
 	//
 	Manual(col *Collection, alias bool) error
 	// ModelToCollection : converts a model to a typesense collection , useful for manual migration
+	//
+	// field names come from the json tag (falling back to the db tag , then the snake cased go name) ,
+	// `json:"-"` fields are skipped and `omitempty` fields are optional . nested structs are mapped to object / object[]
+	// fields with enable_nested_fields turned on
 	ModelToCollection() (*Collection, error)
 	// MustAuto : Must auto migrate basically calls AutoMigrate and panics on failure
 	MustAuto()
@@ -76,6 +85,59 @@ type IMigration[T any] interface {
 	MustManual(col *Collection, alias bool)
 	// NewCollection : create a new collection
 	NewCollection(col *Collection) error
+	// Plan : runs the same comparison as Auto against the server and returns what it would change , without changing anything
+	//
+	// Example:
+	//			migration := typesense.NewModelMigration[MyCoolModel]("<api_key>","<http_server_url>",false)
+	//			plan, err := migration.Plan()
+	//			if err != nil {
+	//				log.Fatal(err)
+	//			}
+	//			fmt.Print(plan) // or plan.JSON()
+	//
+	Plan() (*MigrationPlan, error)
+	// PlanManual : runs the same comparison as Manual against the server and returns what it would change , without changing anything
+	PlanManual(col *Collection, alias bool) (*MigrationPlan, error)
+	// PruneVersions : deletes old versioned collections of an alias (see VersionCollectionName) .
+	// the collection the alias currently points to is never deleted , the newest `keep` versions besides it are retained .
+	//
+	// Example:
+	//			// my_cool_model -> my_cool_model_2022-10-12_<SomeHash>
+	//			// keeps my_cool_model_2022-10-12_<SomeHash> + my_cool_model_2022-10-11_<SomeHash>
+	//			// deletes my_cool_model_2022-10-10_<SomeHash> and anything older
+	//			deleted, err := migration.PruneVersions("my_cool_model", 1)
+	//
+	PruneVersions(aliasName string, keep int) (deleted []string, err error)
+	// PruneVersionsDryRun : same as PruneVersions but only returns the collections that would be deleted
+	PruneVersionsDryRun(aliasName string, keep int) (toDelete []string, err error)
+	// Reindex : for schema changes typesense can't apply in place (ie default sorting field).
+	// Creates a new versioned collection for the alias , copies every document from the currently aliased
+	// collection into it (through the optional transform) and then points the alias at the new collection.
+	//
+	// The previous collection is left untouched . a collection without an alias (ie adopted by Manual / Auto) is
+	// copied too , the alias is then created with its name (typesense resolves the alias before the collection)
+	//
+	// Example:
+	//			migration := typesense.NewModelMigration[MyCoolModel]("<api_key>","<http_server_url>",false)
+	//			col, _ := migration.ModelToCollection()
+	//			// my_cool_model -> my_cool_model_2022-10-10_<SomeHash> becomes
+	//			// my_cool_model -> my_cool_model_2022-10-11_<SomeOtherHash>
+	//			err := migration.Reindex(col, func(doc map[string]interface{}) (map[string]interface{}, error) {
+	//				doc["full_name"] = fmt.Sprintf("%s %s", doc["first_name"], doc["last_name"])
+	//				return doc, nil
+	//			})
+	//
+	Reindex(col *Collection, transform ReindexTransform) error
+	// Rollback : points the alias back to the version created before the one it currently points to .
+	// returns the collection the alias pointed to before the rollback and the one it points to now
+	//
+	// Example:
+	//			// my_cool_model -> my_cool_model_2022-10-11_<SomeHash> becomes
+	//			// my_cool_model -> my_cool_model_2022-10-10_<SomeOtherHash>
+	//			from, to, err := migration.Rollback("my_cool_model")
+	//			log.Printf("rolled back %s -> %s", from, to)
+	//
+	Rollback(aliasName string) (from string, to string, err error)
 	// UpdateCollection : updates collection schema
 	UpdateCollection(colName string, col *CollectionUpdate) error
 	// VersionCollectionName : adds a version to the collectioName
@@ -127,19 +189,14 @@ type Migration[T any] struct {
 //				migration.Auto()
 //			}
 //
+// a collection named after the model that was created without an alias (older versions of Auto did not alias)
+// is adopted and updated in place , it is not aliased
 func (m Migration[T]) Auto() error {
 	colSchema, err := m.ModelToCollection()
 	if err != nil {
 		return err
 	}
-	return m.Manual(colSchema, false)
-}
-
-func sortFieldsFunc(col *Collection, wg *sync.WaitGroup) {
-	defer wg.Done()
-	sort.Slice(col.Fields, func(i, j int) bool {
-		return strings.ToLower(col.Fields[i].Name) < strings.ToLower(col.Fields[j].Name)
-	})
+	return m.Manual(colSchema, true)
 }
 
 // Manual : if you don't trust auto migration , you can always migrate it yourself
@@ -147,6 +204,8 @@ func sortFieldsFunc(col *Collection, wg *sync.WaitGroup) {
 //
 // Note that the implementation uses aliasing .
 //
+// If the collection already exists , the field differences are applied in place (see DiffCollection)
+//
 // Example:
 //			// Your Model you want to map to the client
 //			type MyCoolModel struct {
@@ -164,36 +223,30 @@ func sortFieldsFunc(col *Collection, wg *sync.WaitGroup) {
 //			}
 //
 func (m Migration[T]) Manual(col *Collection, alias bool) error {
-	var wg sync.WaitGroup
-	var typeSenseCollection Collection
-	var colExists bool
 	aliasName := col.Name
-	colCompareCopy := *col
-	if alias {
-		colExists, typeSenseCollection = m.GetCollectionFromAlias(aliasName)
-	} else {
-		colExists, typeSenseCollection = m.GetCollection(col.Name)
+	plan, err := m.PlanManual(col, alias)
+	if err != nil {
+		return err
 	}
-	spew.Dump(colExists)
-	// if exist , we're doing a put
-	if colExists {
-		colCompareCopy.Name = typeSenseCollection.Name
-		// they're both doing the same thing
-		// why not have it concurrent
-		wg.Add(1)
-		go sortFieldsFunc(&typeSenseCollection, &wg)
-		wg.Add(1)
-		go sortFieldsFunc(&colCompareCopy, &wg)
-		wg.Wait()
-
-		// compare changes via diff
-		// gurad check if the one in the backend matches the input
-		if reflect.DeepEqual(col, typeSenseCollection) {
-			return nil
+	if !plan.HasChanges() {
+		return nil
+	}
+	if plan.NeedsReindex() {
+		return fmt.Errorf("Typesense : %s cannot be updated in place , use Reindex\n%s", aliasName, plan)
+	}
+	// if exist , we're doing a patch with the field differences
+	if plan.Collection != "" {
+		err := m.UpdateCollection(plan.Collection, plan.Diff.ToUpdate())
+		if err != nil {
+			return err
 		}
-
-		panic("Typesense Error : Update is : unimplemented yet sorry :(")
-
+		return m.recordMigration(MigrationRecord{
+			Alias:          aliasName,
+			Action:         MigrationActionUpdate,
+			FromCollection: plan.Collection,
+			ToCollection:   plan.Collection,
+			Diff:           plan.Diff,
+		})
 	}
 
 	if alias {
@@ -201,7 +254,7 @@ func (m Migration[T]) Manual(col *Collection, alias bool) error {
 	}
 
 	// otherwise we're doing a post request
-	err := m.NewCollection(col)
+	err = m.NewCollection(col)
 	if err != nil {
 		return err
 	}
@@ -215,7 +268,12 @@ func (m Migration[T]) Manual(col *Collection, alias bool) error {
 		}
 	}
 
-	return nil
+	return m.recordMigration(MigrationRecord{
+		Alias:        aliasName,
+		Action:       MigrationActionCreate,
+		ToCollection: col.Name,
+		Diff:         CollectionDiff{Added: col.Fields},
+	})
 
 }
 
@@ -277,9 +335,11 @@ func (m Migration[T]) UpdateCollection(colName string, col *CollectionUpdate) er
 
 // DeleteCollection : deletes collection and collection data
 func (m Migration[T]) DeleteCollection(colName string, col *CollectionUpdate) error {
-	res, err := m.Req().
-		SetBody(col).
-		Delete(fmt.Sprintf("/collections/%s", colName))
+	req := m.Req()
+	if col != nil {
+		req.SetBody(col)
+	}
+	res, err := req.Delete(fmt.Sprintf("/collections/%s", colName))
 
 	if err != nil {
 		return err
@@ -290,50 +350,22 @@ func (m Migration[T]) DeleteCollection(colName string, col *CollectionUpdate) er
 }
 
 // ModelToCollection : converts a model to a typesense collection , useful for manual migration
+//
+// field names come from the json tag (falling back to the db tag , then the snake cased go name) ,
+// `json:"-"` fields are skipped and `omitempty` fields are optional . nested structs are mapped to object / object[]
+// fields with enable_nested_fields turned on , their fields with tsense tags are added as parent.child fields
 func (m Migration[T]) ModelToCollection() (*Collection, error) {
-	var col Collection
-	var defaultSort string
-	col.Name = m.getCollectionName()
 	var s T
-
-	// Fields will list every structure exportable fields.
-	// Here, it's content would be equal to:
-	// []string{"FirstField", "SecondField", "ThirdField"}
-	obj := reflector.New(s)
-	fieldsGoLang := obj.FieldsFlattened()
-	for _, field := range fieldsGoLang {
-		colVal, _ := field.Tag("db")
-		tType, err := m.golangToTypesenseType(field)
-		if err != nil {
-			return nil, err
-		}
-		sortVal, _ := field.Tag(TagSort)
-		indexVal, _ := field.Tag(TagIndex)
-		requiredVal, _ := field.Tag(TagRequired)
-		facetVal, _ := field.Tag(TagFacet)
-		overrideTypeVal, _ := field.Tag(TagTypeOverride)
-		defaultSortVal, _ := field.Tag(TagDefaultSort)
-
-		if defaultSortVal != "" {
-			if defaultSort != "" {
-				return nil, fmt.Errorf("Typesense : You cannot have more than 1 default sort field")
-			}
-			defaultSort = colVal
-			requiredVal = "1"
-			indexVal = "1"
-		}
-
-		col.Fields = append(col.Fields, CollectionField{
-			Facet:    facetVal != "",
-			Index:    indexVal != "",
-			Optional: requiredVal == "",
-			Sort:     sortVal != "",
-			Name:     colVal,
-			Type:     conditional.Ternary(overrideTypeVal != "", overrideTypeVal, tType),
-		})
+	schema, err := newModelSchema(reflect.TypeOf(&s).Elem())
+	if err != nil {
+		return nil, err
 	}
-	col.DefaultSortingField = defaultSort
-	return &col, nil
+	return &Collection{
+		Name:                m.getCollectionName(),
+		Fields:              schema.Fields,
+		DefaultSortingField: schema.DefaultSort,
+		EnableNestedFields:  schema.HasNested,
+	}, nil
 }
 
 // MustAuto : Must auto migrate basically calls AutoMigrate and panics on failure
@@ -0,0 +1,136 @@
+package typesense
+
+import (
+	"sort"
+	"strings"
+)
+
+// CollectionFieldChange : a field that exists on both schemas but with different settings
+type CollectionFieldChange struct {
+	From CollectionField `json:"from"`
+	To   CollectionField `json:"to"`
+}
+
+// CollectionDiff : field level difference between a live collection and the desired one
+type CollectionDiff struct {
+	Added   []CollectionField       `json:"added,omitempty"`
+	Dropped []CollectionField       `json:"dropped,omitempty"`
+	Changed []CollectionFieldChange `json:"changed,omitempty"`
+}
+
+// IsEmpty : true if both schemas have the same fields
+func (d CollectionDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Dropped) == 0 && len(d.Changed) == 0
+}
+
+// ToUpdate : converts the diff to a schema update typesense can apply in place
+//
+// dropped fields are sent with the drop flag , changed fields are dropped and added again with the new definition
+func (d CollectionDiff) ToUpdate() *CollectionUpdate {
+	var update CollectionUpdate
+	for _, field := range d.Dropped {
+		update.Fields = append(update.Fields, CollectionFieldUpdate{Name: field.Name, Drop: true})
+	}
+	for _, change := range d.Changed {
+		update.Fields = append(update.Fields, CollectionFieldUpdate{Name: change.From.Name, Drop: true})
+		update.Fields = append(update.Fields, fieldToUpdate(change.To))
+	}
+	for _, field := range d.Added {
+		update.Fields = append(update.Fields, fieldToUpdate(field))
+	}
+	return &update
+}
+
+func fieldToUpdate(field CollectionField) CollectionFieldUpdate {
+	return CollectionFieldUpdate{
+		Facet:    field.Facet,
+		Index:    field.Index,
+		Optional: field.Optional,
+		Sort:     field.Sort,
+		Name:     field.Name,
+		Type:     field.Type,
+		NumDim:   field.NumDim,
+		VecDist:  field.VecDist,
+		Embed:    field.Embed,
+	}
+}
+
+func isFieldChanged(from CollectionField, to CollectionField) bool {
+	return from.Type != to.Type ||
+		from.Facet != to.Facet ||
+		from.Index != to.Index ||
+		from.Optional != to.Optional ||
+		from.Sort != to.Sort ||
+		// typesense fills these in when they're not set (ie num_dim of embedding fields)
+		(to.NumDim != 0 && from.NumDim != to.NumDim) ||
+		(to.VecDist != "" && from.VecDist != to.VecDist) ||
+		isEmbedChanged(from.Embed, to.Embed)
+}
+
+// isEmbedChanged : compares the source fields and model (typesense does not return api keys as they were sent)
+func isEmbedChanged(from *FieldEmbed, to *FieldEmbed) bool {
+	if from == nil || to == nil {
+		return from != to
+	}
+	return from.ModelConfig.ModelName != to.ModelConfig.ModelName ||
+		strings.Join(from.From, ",") != strings.Join(to.From, ",")
+}
+
+// DiffCollection : compares the live collection against the desired one field by field (matched by name)
+//
+// Example:
+//			_, live := migration.GetCollectionFromAlias("my_cool_model")
+//			desired, _ := migration.ModelToCollection()
+//			diff := typesense.DiffCollection(&live, desired)
+//			if !diff.IsEmpty() {
+//				err := migration.UpdateCollection(live.Name, diff.ToUpdate())
+//			}
+func DiffCollection(live *Collection, desired *Collection) CollectionDiff {
+	var diff CollectionDiff
+	liveFields := make(map[string]CollectionField, len(live.Fields))
+	desiredFields := make(map[string]CollectionField, len(desired.Fields))
+	for _, field := range live.Fields {
+		liveFields[field.Name] = field
+	}
+	for _, field := range desired.Fields {
+		desiredFields[field.Name] = field
+	}
+
+	for _, field := range desired.Fields {
+		liveField, exists := liveFields[field.Name]
+		if !exists {
+			diff.Added = append(diff.Added, field)
+		} else if isFieldChanged(liveField, field) {
+			diff.Changed = append(diff.Changed, CollectionFieldChange{From: liveField, To: field})
+		}
+	}
+	for _, field := range live.Fields {
+		if _, exists := desiredFields[field.Name]; !exists && !isNestedChild(field.Name, desired.Fields) {
+			diff.Dropped = append(diff.Dropped, field)
+		}
+	}
+
+	sortFields(diff.Added)
+	sortFields(diff.Dropped)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return strings.ToLower(diff.Changed[i].To.Name) < strings.ToLower(diff.Changed[j].To.Name)
+	})
+	return diff
+}
+
+// isNestedChild : typesense lists the flattened children of object fields (ie address.city) in the live schema ,
+// they belong to the object field and are not dropped
+func isNestedChild(name string, fields []CollectionField) bool {
+	for _, field := range fields {
+		if strings.HasPrefix(field.Type, "object") && strings.HasPrefix(name, field.Name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func sortFields(fields []CollectionField) {
+	sort.Slice(fields, func(i, j int) bool {
+		return strings.ToLower(fields[i].Name) < strings.ToLower(fields[j].Name)
+	})
+}
@@ -0,0 +1,154 @@
+package typesense
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiffCollection(t *testing.T) {
+	name := CollectionField{Name: "name", Type: "string", Index: true}
+	age := CollectionField{Name: "age", Type: "int32", Index: true, Sort: true}
+	email := CollectionField{Name: "email", Type: "string", Index: true, Facet: true}
+
+	tests := []struct {
+		name    string
+		live    []CollectionField
+		desired []CollectionField
+		want    CollectionDiff
+	}{
+		{
+			name:    "same fields",
+			live:    []CollectionField{name, age},
+			desired: []CollectionField{age, name},
+			want:    CollectionDiff{},
+		},
+		{
+			name:    "added field",
+			live:    []CollectionField{name},
+			desired: []CollectionField{name, email, age},
+			want:    CollectionDiff{Added: []CollectionField{age, email}},
+		},
+		{
+			name:    "dropped field",
+			live:    []CollectionField{name, email, age},
+			desired: []CollectionField{name},
+			want:    CollectionDiff{Dropped: []CollectionField{age, email}},
+		},
+		{
+			name:    "changed type",
+			live:    []CollectionField{name, age},
+			desired: []CollectionField{name, {Name: "age", Type: "int64", Index: true, Sort: true}},
+			want: CollectionDiff{Changed: []CollectionFieldChange{
+				{From: age, To: CollectionField{Name: "age", Type: "int64", Index: true, Sort: true}},
+			}},
+		},
+		{
+			name:    "changed facet",
+			live:    []CollectionField{email},
+			desired: []CollectionField{{Name: "email", Type: "string", Index: true}},
+			want: CollectionDiff{Changed: []CollectionFieldChange{
+				{From: email, To: CollectionField{Name: "email", Type: "string", Index: true}},
+			}},
+		},
+		{
+			name:    "num dim filled in by typesense",
+			live:    []CollectionField{{Name: "embedding", Type: "float[]", NumDim: 384, VecDist: "cosine"}},
+			desired: []CollectionField{{Name: "embedding", Type: "float[]"}},
+			want:    CollectionDiff{},
+		},
+		{
+			name: "flattened children of object fields",
+			live: []CollectionField{
+				name,
+				{Name: "address", Type: "object"},
+				{Name: "address.city", Type: "string", Index: true},
+				{Name: "tags.label", Type: "string[]", Index: true},
+				{Name: "addresses.zip", Type: "string[]", Index: true},
+			},
+			desired: []CollectionField{
+				name,
+				{Name: "address", Type: "object"},
+				{Name: "addresses", Type: "object[]"},
+			},
+			want: CollectionDiff{
+				Added:   []CollectionField{{Name: "addresses", Type: "object[]"}},
+				Dropped: []CollectionField{{Name: "tags.label", Type: "string[]", Index: true}},
+			},
+		},
+		{
+			name: "added , dropped and changed",
+			live: []CollectionField{name, email},
+			desired: []CollectionField{
+				{Name: "name", Type: "string", Index: true, Optional: true},
+				age,
+			},
+			want: CollectionDiff{
+				Added:   []CollectionField{age},
+				Dropped: []CollectionField{email},
+				Changed: []CollectionFieldChange{
+					{From: name, To: CollectionField{Name: "name", Type: "string", Index: true, Optional: true}},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diff := DiffCollection(&Collection{Fields: test.live}, &Collection{Fields: test.desired})
+			if !reflect.DeepEqual(diff, test.want) {
+				t.Fatalf("DiffCollection() = %+v , want %+v", diff, test.want)
+			}
+			if diff.IsEmpty() != reflect.DeepEqual(test.want, CollectionDiff{}) {
+				t.Fatalf("IsEmpty() = %v", diff.IsEmpty())
+			}
+		})
+	}
+}
+
+func TestCollectionDiffToUpdate(t *testing.T) {
+	diff := CollectionDiff{
+		Added:   []CollectionField{{Name: "nick_name", Type: "string", Optional: true}},
+		Dropped: []CollectionField{{Name: "email", Type: "string", Facet: true}},
+		Changed: []CollectionFieldChange{{
+			From: CollectionField{Name: "visit", Type: "int64"},
+			To:   CollectionField{Name: "visit", Type: "int32", Sort: true},
+		}},
+	}
+
+	want := []CollectionFieldUpdate{
+		{Name: "email", Drop: true},
+		{Name: "visit", Drop: true},
+		{Name: "visit", Type: "int32", Sort: true},
+		{Name: "nick_name", Type: "string", Optional: true},
+	}
+	update := diff.ToUpdate()
+	if !reflect.DeepEqual(update.Fields, want) {
+		t.Fatalf("ToUpdate() = %+v , want %+v", update.Fields, want)
+	}
+
+	body, err := json.Marshal(update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := `{"fields":[` +
+		`{"drop":true,"name":"email"},` +
+		`{"drop":true,"name":"visit"},` +
+		`{"facet":false,"index":false,"optional":false,"sort":true,"name":"visit","type":"int32"},` +
+		`{"facet":false,"index":false,"optional":true,"sort":false,"name":"nick_name","type":"string"}` +
+		`]}`
+	if string(body) != wantJSON {
+		t.Fatalf("json = %s , want %s", body, wantJSON)
+	}
+}
+
+func TestCollectionFieldUpdateDropJSON(t *testing.T) {
+	// only the name goes with the drop flag , typesense rejects the other settings
+	body, err := json.Marshal(CollectionFieldUpdate{Name: "email", Type: "string", Facet: true, Index: true, Drop: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"drop":true,"name":"email"}` {
+		t.Fatalf("json = %s", body)
+	}
+}
@@ -0,0 +1,181 @@
+package typesense
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/baderkha/typesense/filter"
+	http2 "github.com/baderkha/typesense/pkg/http"
+	"github.com/lithammer/shortuuid/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MigrationHistoryCollection : reserved collection the migration client records its schema changes in
+	MigrationHistoryCollection = "_typesense_migrations"
+
+	// MigrationActionCreate : a collection (and alias) was created
+	MigrationActionCreate = "create"
+	// MigrationActionUpdate : the fields of a collection were updated in place
+	MigrationActionUpdate = "update"
+	// MigrationActionReindex : documents were copied to a new versioned collection and the alias moved
+	MigrationActionReindex = "reindex"
+	// MigrationActionRollback : the alias was moved back to a previous version
+	MigrationActionRollback = "rollback"
+)
+
+var (
+	hasMigrationHistory = false
+	migrationAppVersion = ""
+)
+
+// SetMigrationHistory : turns recording of schema changes to the MigrationHistoryCollection on / off (off by default) .
+// the api key needs access to the MigrationHistoryCollection , a change that could not be recorded makes the migration
+// return an error even though it was applied
+func SetMigrationHistory(isMigrationHistory bool) {
+	hasMigrationHistory = isMigrationHistory
+}
+
+// SetMigrationAppVersion : the version of your app , stored alongside each recorded schema change
+func SetMigrationAppVersion(appVersion string) {
+	migrationAppVersion = appVersion
+}
+
+// MigrationRecord : a schema change applied by the migration client
+type MigrationRecord struct {
+	ID             string         `json:"id"`
+	Alias          string         `json:"alias"`
+	Action         string         `json:"action"`
+	FromCollection string         `json:"from_collection"`
+	ToCollection   string         `json:"to_collection"`
+	Diff           CollectionDiff `json:"diff"`
+	Timestamp      int64          `json:"timestamp"` // unix milliseconds
+	AppVersion     string         `json:"app_version"`
+}
+
+// migrationRecordDocument : how a MigrationRecord is stored in typesense (the diff is kept as a json string)
+type migrationRecordDocument struct {
+	ID             string `json:"id"`
+	Alias          string `json:"alias"`
+	Action         string `json:"action"`
+	FromCollection string `json:"from_collection"`
+	ToCollection   string `json:"to_collection"`
+	Diff           string `json:"diff"`
+	Timestamp      int64  `json:"timestamp"`
+	AppVersion     string `json:"app_version"`
+}
+
+func migrationHistorySchema() *Collection {
+	return &Collection{
+		Name: MigrationHistoryCollection,
+		Fields: []CollectionField{
+			{Name: "alias", Type: "string", Facet: true, Index: true},
+			{Name: "action", Type: "string", Facet: true, Index: true},
+			{Name: "from_collection", Type: "string", Index: true, Optional: true},
+			{Name: "to_collection", Type: "string", Index: true, Optional: true},
+			{Name: "diff", Type: "string", Optional: true},
+			{Name: "timestamp", Type: "int64", Index: true, Sort: true},
+			{Name: "app_version", Type: "string", Facet: true, Index: true, Optional: true},
+		},
+		DefaultSortingField: "timestamp",
+	}
+}
+
+// History : lists the recorded schema changes for an alias (or collection name when not aliased) newest first
+func (m Migration[T]) History(aliasName string) ([]MigrationRecord, error) {
+	if exists, _ := m.GetCollection(MigrationHistoryCollection); !exists {
+		return nil, nil
+	}
+	body, err := m.exportStream(MigrationHistoryCollection, map[string]string{
+		"filter_by": "alias:=" + filter.Quote(aliasName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var records []MigrationRecord
+	reader := bufio.NewReader(body)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return nil, errors.Wrap(readErr, typesenseErrPrefix)
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			var doc migrationRecordDocument
+			err := json.Unmarshal(line, &doc)
+			if err != nil {
+				return nil, errors.Wrap(err, typesenseErrPrefix)
+			}
+			record := MigrationRecord{
+				ID:             doc.ID,
+				Alias:          doc.Alias,
+				Action:         doc.Action,
+				FromCollection: doc.FromCollection,
+				ToCollection:   doc.ToCollection,
+				Timestamp:      doc.Timestamp,
+				AppVersion:     doc.AppVersion,
+			}
+			if doc.Diff != "" {
+				_ = json.Unmarshal([]byte(doc.Diff), &record.Diff)
+			}
+			records = append(records, record)
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp > records[j].Timestamp
+	})
+	return records, nil
+}
+
+// recordMigration : stores a schema change in the MigrationHistoryCollection (if history is turned on)
+func (m Migration[T]) recordMigration(record MigrationRecord) error {
+	if !hasMigrationHistory {
+		return nil
+	}
+	err := m.ensureMigrationHistory()
+	if err != nil {
+		return errors.Wrap(err, "Typesense : migration applied but history could not be recorded")
+	}
+
+	diff, _ := json.Marshal(record.Diff)
+	res, err := m.Req().
+		SetBody(&migrationRecordDocument{
+			ID:             shortuuid.New(),
+			Alias:          record.Alias,
+			Action:         record.Action,
+			FromCollection: record.FromCollection,
+			ToCollection:   record.ToCollection,
+			Diff:           string(diff),
+			Timestamp:      time.Now().UnixMilli(),
+			AppVersion:     migrationAppVersion,
+		}).
+		Post(fmt.Sprintf("/collections/%s/documents", MigrationHistoryCollection))
+	if err != nil {
+		return errors.Wrap(err, "Typesense : migration applied but history could not be recorded")
+	} else if !http2.StatusIsSuccess(res.StatusCode()) {
+		return errors.Wrap(
+			typesenseToError(res.Body(), res.StatusCode()),
+			"Typesense : migration applied but history could not be recorded",
+		)
+	}
+	return nil
+}
+
+// ensureMigrationHistory : creates the MigrationHistoryCollection if it's not there yet
+func (m Migration[T]) ensureMigrationHistory() error {
+	if exists, _ := m.GetCollection(MigrationHistoryCollection); exists {
+		return nil
+	}
+	return m.NewCollection(migrationHistorySchema())
+}
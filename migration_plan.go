@@ -0,0 +1,204 @@
+package typesense
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/baderkha/typesense/pkg/conditional"
+)
+
+const (
+	// PlanActionCreateCollection : the collection does not exist and will be created
+	PlanActionCreateCollection = "create_collection"
+	// PlanActionCreateAlias : the alias does not exist and will be pointed at the created collection
+	PlanActionCreateAlias = "create_alias"
+	// PlanActionAddField : the field will be added to the collection
+	PlanActionAddField = "add_field"
+	// PlanActionDropField : the field will be dropped from the collection
+	PlanActionDropField = "drop_field"
+	// PlanActionModifyField : the field will be dropped and added again with the new definition
+	PlanActionModifyField = "modify_field"
+	// PlanActionReindex : the change can't be applied in place , use Reindex / AutoReindex
+	PlanActionReindex = "reindex"
+)
+
+// MigrationPlanStep : a single change the migration would apply
+type MigrationPlanStep struct {
+	Action string           `json:"action"`
+	Target string           `json:"target,omitempty"`
+	Field  string           `json:"field,omitempty"`
+	From   *CollectionField `json:"from,omitempty"`
+	To     *CollectionField `json:"to,omitempty"`
+	Reason string           `json:"reason,omitempty"`
+}
+
+// MigrationPlan : what Manual / Auto would change on the server , built without changing anything
+type MigrationPlan struct {
+	// Name : the alias name (or collection name when not aliased)
+	Name    string `json:"name"`
+	Aliased bool   `json:"aliased"`
+	// Collection : the live collection the changes apply to , empty if it will be created
+	Collection string              `json:"collection,omitempty"`
+	Diff       CollectionDiff      `json:"diff"`
+	Steps      []MigrationPlanStep `json:"steps"`
+}
+
+// HasChanges : true if applying the plan would change anything
+func (p *MigrationPlan) HasChanges() bool {
+	return len(p.Steps) > 0
+}
+
+// NeedsReindex : true if the plan can only be applied with Reindex / AutoReindex
+func (p *MigrationPlan) NeedsReindex() bool {
+	for _, step := range p.Steps {
+		if step.Action == PlanActionReindex {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON : the plan as indented json (for ci tooling)
+func (p *MigrationPlan) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// String : the plan as human readable text
+//
+// Example:
+//			Migration plan for user_data (alias -> user_data_2022-10-10_<SomeHash>)
+//			  + add field nick_name (string , optional)
+//			  - drop field email (string , facet)
+//			  ~ modify field visit (int64 -> int32)
+func (p *MigrationPlan) String() string {
+	var b strings.Builder
+	target := conditional.Ternary(p.Collection != "", p.Collection, "new collection")
+	if p.Aliased {
+		fmt.Fprintf(&b, "Migration plan for %s (alias -> %s)\n", p.Name, target)
+	} else {
+		fmt.Fprintf(&b, "Migration plan for %s (%s)\n", p.Name, target)
+	}
+	if !p.HasChanges() {
+		b.WriteString("  no changes\n")
+		return b.String()
+	}
+	for _, step := range p.Steps {
+		switch step.Action {
+		case PlanActionCreateCollection:
+			fmt.Fprintf(&b, "  + create collection %s\n", step.Target)
+		case PlanActionCreateAlias:
+			fmt.Fprintf(&b, "  + create alias %s -> %s\n", p.Name, step.Target)
+		case PlanActionAddField:
+			fmt.Fprintf(&b, "  + add field %s (%s)\n", step.Field, describeField(*step.To))
+		case PlanActionDropField:
+			fmt.Fprintf(&b, "  - drop field %s (%s)\n", step.Field, describeField(*step.From))
+		case PlanActionModifyField:
+			fmt.Fprintf(&b, "  ~ modify field %s (%s -> %s)\n", step.Field, describeField(*step.From), describeField(*step.To))
+		case PlanActionReindex:
+			fmt.Fprintf(&b, "  ! reindex required : %s\n", step.Reason)
+		}
+	}
+	return b.String()
+}
+
+func describeField(field CollectionField) string {
+	attributes := []string{field.Type}
+	if field.Optional {
+		attributes = append(attributes, "optional")
+	}
+	if field.Index {
+		attributes = append(attributes, "index")
+	}
+	if field.Facet {
+		attributes = append(attributes, "facet")
+	}
+	if field.Sort {
+		attributes = append(attributes, "sort")
+	}
+	return strings.Join(attributes, " , ")
+}
+
+// Plan : runs the same comparison as Auto against the server and returns what it would change , without changing anything
+//
+// Example:
+//			migration := typesense.NewModelMigration[MyCoolModel]("<api_key>","<http_server_url>",false)
+//			plan, err := migration.Plan()
+//			if err != nil {
+//				log.Fatal(err)
+//			}
+//			fmt.Print(plan) // or plan.JSON()
+//
+func (m Migration[T]) Plan() (*MigrationPlan, error) {
+	colSchema, err := m.ModelToCollection()
+	if err != nil {
+		return nil, err
+	}
+	return m.PlanManual(colSchema, true)
+}
+
+// PlanManual : runs the same comparison as Manual against the server and returns what it would change , without changing anything
+func (m Migration[T]) PlanManual(col *Collection, alias bool) (*MigrationPlan, error) {
+	var typeSenseCollection Collection
+	var colExists bool
+	plan := MigrationPlan{
+		Name:    col.Name,
+		Aliased: alias,
+	}
+	if alias {
+		colExists, typeSenseCollection = m.GetCollectionFromAlias(col.Name)
+	}
+	if !colExists {
+		// also adopts collections created without an alias (ie by Auto before it aliased) , they're updated in place
+		// instead of creating an empty versioned collection + an alias shadowing them
+		colExists, typeSenseCollection = m.GetCollection(col.Name)
+		plan.Aliased = alias && !colExists
+	}
+
+	if !colExists {
+		newColName := conditional.Ternary(alias, fmt.Sprintf("%s_<date>_<version>", col.Name), col.Name)
+		plan.Diff = CollectionDiff{Added: col.Fields}
+		plan.Steps = append(plan.Steps, MigrationPlanStep{Action: PlanActionCreateCollection, Target: newColName})
+		if alias {
+			plan.Steps = append(plan.Steps, MigrationPlanStep{Action: PlanActionCreateAlias, Target: newColName})
+		}
+		return &plan, nil
+	}
+
+	plan.Collection = typeSenseCollection.Name
+	plan.Diff = DiffCollection(&typeSenseCollection, col)
+	if typeSenseCollection.DefaultSortingField != col.DefaultSortingField {
+		plan.Steps = append(plan.Steps, MigrationPlanStep{
+			Action: PlanActionReindex,
+			Reason: fmt.Sprintf(
+				"default sorting field changed from '%s' to '%s'",
+				typeSenseCollection.DefaultSortingField,
+				col.DefaultSortingField,
+			),
+		})
+	}
+	if col.EnableNestedFields && !typeSenseCollection.EnableNestedFields {
+		plan.Steps = append(plan.Steps, MigrationPlanStep{
+			Action: PlanActionReindex,
+			Reason: "nested object fields need enable_nested_fields which can only be set when creating the collection",
+		})
+	}
+	for i := range plan.Diff.Added {
+		field := plan.Diff.Added[i]
+		plan.Steps = append(plan.Steps, MigrationPlanStep{Action: PlanActionAddField, Field: field.Name, To: &field})
+	}
+	for i := range plan.Diff.Dropped {
+		field := plan.Diff.Dropped[i]
+		plan.Steps = append(plan.Steps, MigrationPlanStep{Action: PlanActionDropField, Field: field.Name, From: &field})
+	}
+	for i := range plan.Diff.Changed {
+		change := plan.Diff.Changed[i]
+		plan.Steps = append(plan.Steps, MigrationPlanStep{
+			Action: PlanActionModifyField,
+			Field:  change.To.Name,
+			From:   &change.From,
+			To:     &change.To,
+		})
+	}
+	return &plan, nil
+}
@@ -0,0 +1,46 @@
+package typesense
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPlanManualAdoptsUnaliasedCollection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/aliases/my_model":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		case "/collections/my_model":
+			_ = json.NewEncoder(w).Encode(Collection{
+				Name:   "my_model",
+				Fields: []CollectionField{{Name: "name", Type: "string", Index: true}},
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	migration := NewManualMigration("key", server.URL, false)
+	plan, err := migration.PlanManual(&Collection{
+		Name: "my_model",
+		Fields: []CollectionField{
+			{Name: "name", Type: "string", Index: true},
+			{Name: "age", Type: "int32", Index: true},
+		},
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Collection != "my_model" || plan.Aliased {
+		t.Fatalf("plan = %+v , want the unaliased my_model collection to be updated in place", plan)
+	}
+	if len(plan.Steps) != 1 || plan.Steps[0].Action != PlanActionAddField || plan.Steps[0].Field != "age" {
+		t.Fatalf("steps = %+v , want a single add field step", plan.Steps)
+	}
+}
@@ -0,0 +1,154 @@
+package typesense
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// reindexChunkSize : how many documents are sent per import request when reindexing
+	reindexChunkSize = 1000
+)
+
+// ReindexTransform : transforms a document before it's written to the new collection
+//
+// return a nil document to skip it , return an error to abort the reindex
+type ReindexTransform func(doc map[string]interface{}) (map[string]interface{}, error)
+
+// AutoReindex : same as Reindex but the collection schema is built from the model
+func (m Migration[T]) AutoReindex(transform ReindexTransform) error {
+	colSchema, err := m.ModelToCollection()
+	if err != nil {
+		return err
+	}
+	return m.Reindex(colSchema, transform)
+}
+
+// Reindex : for schema changes typesense can't apply in place (ie default sorting field).
+// Creates a new versioned collection for the alias , copies every document from the currently aliased
+// collection into it (through the optional transform) and then points the alias at the new collection.
+//
+// The previous collection is left untouched . a collection without an alias (ie adopted by Manual / Auto) is
+// copied too , the alias is then created with its name (typesense resolves the alias before the collection)
+//
+// Example:
+//			migration := typesense.NewModelMigration[MyCoolModel]("<api_key>","<http_server_url>",false)
+//			col, _ := migration.ModelToCollection()
+//			// my_cool_model -> my_cool_model_2022-10-10_<SomeHash> becomes
+//			// my_cool_model -> my_cool_model_2022-10-11_<SomeOtherHash>
+//			err := migration.Reindex(col, func(doc map[string]interface{}) (map[string]interface{}, error) {
+//				doc["full_name"] = fmt.Sprintf("%s %s", doc["first_name"], doc["last_name"])
+//				return doc, nil
+//			})
+//
+func (m Migration[T]) Reindex(col *Collection, transform ReindexTransform) error {
+	aliasName := col.Name
+	fromColName := aliasName
+	// a collection created without an alias (adopted by Manual / Auto) is copied from the collection of the same name
+	if aliasExists, alias := m.GetAlias(aliasName); aliasExists {
+		fromColName = alias.CollectionName
+	}
+	colExists, oldCol := m.GetCollection(fromColName)
+	if !colExists {
+		return fmt.Errorf("Typesense : %s does not exist , nothing to reindex (use Manual / Auto to create it)", aliasName)
+	}
+
+	newCol := *col
+	newCol.Name = m.VersionCollectionName(aliasName)
+	err := m.NewCollection(&newCol)
+	if err != nil {
+		return err
+	}
+
+	err = m.copyDocuments(fromColName, newCol.Name, transform)
+	if err != nil {
+		// the new collection is incomplete , don't leave it lying around
+		_ = m.DeleteCollection(newCol.Name, nil)
+		return err
+	}
+
+	err = m.AliasCollection(&Alias{
+		Name:           aliasName,
+		CollectionName: newCol.Name,
+	})
+	if err != nil {
+		return err
+	}
+	m.forgetAlias(aliasName)
+	return m.recordMigration(MigrationRecord{
+		Alias:          aliasName,
+		Action:         MigrationActionReindex,
+		FromCollection: fromColName,
+		ToCollection:   newCol.Name,
+		Diff:           DiffCollection(&oldCol, &newCol),
+	})
+}
+
+// copyDocuments : streams the export of one collection into the import of another in chunks
+func (m Migration[T]) copyDocuments(fromColName string, toColName string, transform ReindexTransform) error {
+	body, err := m.exportStream(fromColName, nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	var chunk bytes.Buffer
+	var chunkLen int
+	flush := func() error {
+		if chunkLen == 0 {
+			return nil
+		}
+		err := m.importJSONLines(toColName, DocumentActionCreate, chunk.Bytes())
+		chunk.Reset()
+		chunkLen = 0
+		return err
+	}
+
+	reader := bufio.NewReader(body)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return errors.Wrap(readErr, typesenseErrPrefix)
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			if transform != nil {
+				line, err = transformJSONLine(line, transform)
+				if err != nil {
+					return err
+				}
+			}
+			if line != nil {
+				chunk.Write(line)
+				chunk.WriteByte('\n')
+				chunkLen++
+			}
+		}
+		if chunkLen >= reindexChunkSize || readErr == io.EOF {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+func transformJSONLine(line []byte, transform ReindexTransform) ([]byte, error) {
+	var doc map[string]interface{}
+	err := json.Unmarshal(line, &doc)
+	if err != nil {
+		return nil, errors.Wrap(err, typesenseErrPrefix)
+	}
+	doc, err = transform(doc)
+	if err != nil || doc == nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
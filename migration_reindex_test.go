@@ -0,0 +1,365 @@
+package typesense
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// migrationTestServer : an in memory typesense with collections , documents and aliases
+type migrationTestServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	collections map[string]*migrationTestCollection
+	aliases     map[string]string
+	// imports : number of documents sent per import request
+	imports   []int
+	createdAt int64
+}
+
+type migrationTestCollection struct {
+	schema Collection
+	docs   []string
+}
+
+func newMigrationTestServer(t *testing.T) *migrationTestServer {
+	s := &migrationTestServer{
+		collections: make(map[string]*migrationTestCollection),
+		aliases:     make(map[string]string),
+		createdAt:   1665360000,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		route := r.Method + " " + parts[0]
+		if len(parts) > 1 {
+			route += " {name}"
+		}
+		if len(parts) > 2 {
+			route += " " + strings.Join(parts[2:], "/")
+		}
+
+		switch route {
+		case "GET aliases {name}":
+			if colName, ok := s.aliases[parts[1]]; ok {
+				_ = json.NewEncoder(w).Encode(Alias{Name: parts[1], CollectionName: colName})
+				return
+			}
+		case "PUT aliases {name}":
+			var alias Alias
+			_ = json.NewDecoder(r.Body).Decode(&alias)
+			if s.collections[alias.CollectionName] == nil {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"Collection not found"}`))
+				return
+			}
+			s.aliases[parts[1]] = alias.CollectionName
+			_ = json.NewEncoder(w).Encode(Alias{Name: parts[1], CollectionName: alias.CollectionName})
+			return
+		case "GET collections":
+			cols := []Collection{}
+			for _, col := range s.collections {
+				cols = append(cols, col.withCount())
+			}
+			_ = json.NewEncoder(w).Encode(cols)
+			return
+		case "GET collections {name}":
+			if col, ok := s.collections[parts[1]]; ok {
+				_ = json.NewEncoder(w).Encode(col.withCount())
+				return
+			}
+		case "POST collections":
+			var col Collection
+			_ = json.NewDecoder(r.Body).Decode(&col)
+			if s.collections[col.Name] != nil {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"message":"A collection with this name already exists."}`))
+				return
+			}
+			s.createdAt++
+			col.CreatedAt = s.createdAt
+			s.collections[col.Name] = &migrationTestCollection{schema: col}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(col)
+			return
+		case "DELETE collections {name}":
+			if col, ok := s.collections[parts[1]]; ok {
+				delete(s.collections, parts[1])
+				_ = json.NewEncoder(w).Encode(col.withCount())
+				return
+			}
+		case "GET collections {name} documents/export":
+			if col, ok := s.collections[parts[1]]; ok {
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte(strings.Join(col.docs, "\n")))
+				return
+			}
+		case "POST collections {name} documents/import":
+			col, ok := s.collections[parts[1]]
+			if !ok {
+				break
+			}
+			var results []string
+			scanner := bufio.NewScanner(r.Body)
+			for scanner.Scan() {
+				var doc struct {
+					Bad bool `json:"bad"`
+				}
+				_ = json.Unmarshal(scanner.Bytes(), &doc)
+				if doc.Bad {
+					results = append(results, fmt.Sprintf(`{"success":false,"error":"Field bad is invalid","document":%q}`, scanner.Text()))
+					continue
+				}
+				col.docs = append(col.docs, scanner.Text())
+				results = append(results, `{"success":true}`)
+			}
+			s.imports = append(s.imports, len(results))
+			_, _ = w.Write([]byte(strings.Join(results, "\n")))
+			return
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (c *migrationTestCollection) withCount() Collection {
+	col := c.schema
+	col.NumDocuments = int64(len(c.docs))
+	return col
+}
+
+// addCollection : adds a collection with the documents (created in the order it's added)
+func (s *migrationTestServer) addCollection(name string, docs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.createdAt++
+	s.collections[name] = &migrationTestCollection{
+		schema: Collection{Name: name, Fields: []CollectionField{{Name: "name", Type: "string", Index: true}}, CreatedAt: s.createdAt},
+		docs:   docs,
+	}
+}
+
+func (s *migrationTestServer) collectionNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for name := range s.collections {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (s *migrationTestServer) docs(colName string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if col, ok := s.collections[colName]; ok {
+		return col.docs
+	}
+	return nil
+}
+
+var migrationTestSchema = &Collection{
+	Name: "my_model",
+	Fields: []CollectionField{
+		{Name: "name", Type: "string", Index: true},
+		{Name: "rank", Type: "int32", Index: true},
+	},
+	DefaultSortingField: "rank",
+}
+
+func TestReindexAdoptedCollection(t *testing.T) {
+	server := newMigrationTestServer(t)
+	server.addCollection("my_model", `{"id":"1","name":"a"}`, `{"id":"2","name":"b"}`)
+	migration := NewManualMigration("key", server.URL, false)
+
+	err := migration.Reindex(migrationTestSchema, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newColName := server.aliases["my_model"]
+	if !isCollectionVersionOf("my_model", newColName) {
+		t.Fatalf("aliases = %v , want my_model pointing at a new version", server.aliases)
+	}
+	if got := server.docs(newColName); strings.Join(got, "\n") != `{"id":"1","name":"a"}`+"\n"+`{"id":"2","name":"b"}` {
+		t.Fatalf("new collection documents = %v", got)
+	}
+	if server.collections[newColName].schema.DefaultSortingField != "rank" {
+		t.Fatalf("new collection = %+v", server.collections[newColName].schema)
+	}
+	// the adopted collection is left untouched
+	if len(server.docs("my_model")) != 2 || len(server.collectionNames()) != 2 {
+		t.Fatalf("collections = %v", server.collectionNames())
+	}
+
+	// the next reindex goes through the alias
+	err = migration.Reindex(migrationTestSchema, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest := server.aliases["my_model"]; latest == newColName || len(server.docs(latest)) != 2 {
+		t.Fatalf("aliases = %v", server.aliases)
+	}
+}
+
+func TestReindexMissingCollection(t *testing.T) {
+	server := newMigrationTestServer(t)
+	migration := NewManualMigration("key", server.URL, false)
+
+	err := migration.Reindex(migrationTestSchema, nil)
+	if err == nil || !strings.Contains(err.Error(), "my_model does not exist") {
+		t.Fatalf("Reindex() = %v", err)
+	}
+	if len(server.collectionNames()) != 0 || len(server.aliases) != 0 {
+		t.Fatalf("collections = %v , aliases = %v", server.collectionNames(), server.aliases)
+	}
+}
+
+func migrationTestDocs(n int) []string {
+	docs := make([]string, n)
+	for i := range docs {
+		docs[i] = fmt.Sprintf(`{"id":"%d","name":"doc %d"}`, i, i)
+	}
+	return docs
+}
+
+func TestReindexChunks(t *testing.T) {
+	tests := []struct {
+		name        string
+		docs        int
+		wantImports []int
+	}{
+		{name: "empty collection", docs: 0, wantImports: nil},
+		{name: "single chunk", docs: 3, wantImports: []int{3}},
+		{name: "exactly one full chunk", docs: reindexChunkSize, wantImports: []int{reindexChunkSize}},
+		{name: "last chunk flushed", docs: 2*reindexChunkSize + 500, wantImports: []int{reindexChunkSize, reindexChunkSize, 500}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newMigrationTestServer(t)
+			server.addCollection("my_model_v1", migrationTestDocs(test.docs)...)
+			server.aliases["my_model"] = "my_model_v1"
+			migration := NewManualMigration("key", server.URL, false)
+
+			err := migration.Reindex(migrationTestSchema, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(server.imports) != fmt.Sprint(test.wantImports) {
+				t.Fatalf("imports = %v , want %v", server.imports, test.wantImports)
+			}
+			newColName := server.aliases["my_model"]
+			if newColName == "my_model_v1" || len(server.docs(newColName)) != test.docs {
+				t.Fatalf("alias = %s with %d documents", newColName, len(server.docs(newColName)))
+			}
+			if len(server.docs("my_model_v1")) != test.docs {
+				t.Fatal("the previous collection should be left untouched")
+			}
+		})
+	}
+}
+
+func TestReindexTransform(t *testing.T) {
+	server := newMigrationTestServer(t)
+	server.addCollection("my_model_v1", migrationTestDocs(4)...)
+	server.aliases["my_model"] = "my_model_v1"
+	migration := NewManualMigration("key", server.URL, false)
+
+	err := migration.Reindex(migrationTestSchema, func(doc map[string]interface{}) (map[string]interface{}, error) {
+		// skip the odd documents
+		if doc["id"] == "1" || doc["id"] == "3" {
+			return nil, nil
+		}
+		doc["rank"] = 1
+		return doc, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := server.docs(server.aliases["my_model"])
+	want := []string{`{"id":"0","name":"doc 0","rank":1}`, `{"id":"2","name":"doc 2","rank":1}`}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("documents = %v , want %v", got, want)
+	}
+}
+
+func TestReindexCleanup(t *testing.T) {
+	tests := []struct {
+		name      string
+		docs      []string
+		transform ReindexTransform
+		wantErr   string
+	}{
+		{
+			name:    "import rejected a document",
+			docs:    append(migrationTestDocs(reindexChunkSize), `{"id":"bad","bad":true}`),
+			wantErr: "bad",
+		},
+		{
+			name: "transform error",
+			docs: migrationTestDocs(3),
+			transform: func(doc map[string]interface{}) (map[string]interface{}, error) {
+				if doc["id"] == "2" {
+					return nil, fmt.Errorf("cannot transform %v", doc["id"])
+				}
+				return doc, nil
+			},
+			wantErr: "cannot transform 2",
+		},
+		{
+			name: "invalid json with a transform",
+			docs: []string{`{"id":"1"`},
+			transform: func(doc map[string]interface{}) (map[string]interface{}, error) {
+				return doc, nil
+			},
+			wantErr: "unexpected end of JSON input",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newMigrationTestServer(t)
+			server.addCollection("my_model_v1", test.docs...)
+			server.aliases["my_model"] = "my_model_v1"
+			migration := NewManualMigration("key", server.URL, false)
+
+			err := migration.Reindex(migrationTestSchema, test.transform)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("Reindex() = %v , want %q", err, test.wantErr)
+			}
+			// the incomplete collection is deleted and the alias is not moved
+			if names := server.collectionNames(); len(names) != 1 || names[0] != "my_model_v1" {
+				t.Fatalf("collections = %v", names)
+			}
+			if server.aliases["my_model"] != "my_model_v1" {
+				t.Fatalf("aliases = %v", server.aliases)
+			}
+		})
+	}
+}
+
+func TestReindexImportError(t *testing.T) {
+	server := newMigrationTestServer(t)
+	server.addCollection("my_model_v1", `{"id":"1"}`, `{"id":"2","bad":true}`)
+	server.aliases["my_model"] = "my_model_v1"
+	migration := NewManualMigration("key", server.URL, false)
+
+	err := migration.Reindex(migrationTestSchema, nil)
+	var importErr *ImportError
+	if !errors.As(err, &importErr) {
+		t.Fatalf("Reindex() = %v , want an *ImportError", err)
+	}
+	if len(importErr.FailedIDs) != 1 || importErr.FailedIDs[0] != "2" {
+		t.Fatalf("failed ids = %v", importErr.FailedIDs)
+	}
+}
@@ -0,0 +1,125 @@
+package typesense
+
+import (
+	"fmt"
+)
+
+// PruneVersions : deletes old versioned collections of an alias (see VersionCollectionName) .
+// the collection the alias currently points to is never deleted , the newest `keep` versions besides it are retained .
+//
+// Example:
+//			// my_cool_model -> my_cool_model_2022-10-12_<SomeHash>
+//			// keeps my_cool_model_2022-10-12_<SomeHash> + my_cool_model_2022-10-11_<SomeHash>
+//			// deletes my_cool_model_2022-10-10_<SomeHash> and anything older
+//			deleted, err := migration.PruneVersions("my_cool_model", 1)
+//
+func (m Migration[T]) PruneVersions(aliasName string, keep int) ([]string, error) {
+	toDelete, err := m.PruneVersionsDryRun(aliasName, keep)
+	if err != nil {
+		return nil, err
+	}
+	var deleted []string
+	for _, colName := range toDelete {
+		err := m.DeleteCollection(colName, nil)
+		if err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, colName)
+	}
+	return deleted, nil
+}
+
+// PruneVersionsDryRun : same as PruneVersions but only returns the collections that would be deleted
+func (m Migration[T]) PruneVersionsDryRun(aliasName string, keep int) ([]string, error) {
+	if keep < 0 {
+		return nil, fmt.Errorf("Typesense : cannot keep %d versions of %s", keep, aliasName)
+	}
+	stale, err := m.staleVersions(aliasName)
+	if err != nil {
+		return nil, err
+	}
+	if keep >= len(stale) {
+		return nil, nil
+	}
+
+	var toDelete []string
+	for _, col := range stale[keep:] {
+		toDelete = append(toDelete, col.Name)
+	}
+	return toDelete, nil
+}
+
+// staleVersions : versioned collections of an alias that the alias does not point to (newest first)
+func (m Migration[T]) staleVersions(aliasName string) ([]Collection, error) {
+	aliasExists, alias := m.GetAlias(aliasName)
+	if !aliasExists {
+		return nil, fmt.Errorf("Typesense : alias %s does not exist", aliasName)
+	}
+	cols, err := m.GetCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []Collection
+	for _, col := range cols {
+		if col.Name != alias.CollectionName && isCollectionVersionOf(aliasName, col.Name) {
+			stale = append(stale, col)
+		}
+	}
+	sortCollectionVersions(stale)
+	return stale, nil
+}
+
+// Rollback : points the alias back to the version created before the one it currently points to .
+// returns the collection the alias pointed to before the rollback and the one it points to now
+//
+// Example:
+//			// my_cool_model -> my_cool_model_2022-10-11_<SomeHash> becomes
+//			// my_cool_model -> my_cool_model_2022-10-10_<SomeOtherHash>
+//			from, to, err := migration.Rollback("my_cool_model")
+//			log.Printf("rolled back %s -> %s", from, to)
+//
+func (m Migration[T]) Rollback(aliasName string) (from string, to string, err error) {
+	aliasExists, alias := m.GetAlias(aliasName)
+	if !aliasExists {
+		return "", "", fmt.Errorf("Typesense : alias %s does not exist", aliasName)
+	}
+	from = alias.CollectionName
+	colExists, current := m.GetCollection(from)
+	if !colExists {
+		return from, "", fmt.Errorf("Typesense : collection %s for alias %s does not exist", from, aliasName)
+	}
+	stale, err := m.staleVersions(aliasName)
+	if err != nil {
+		return from, "", err
+	}
+
+	// stale versions are newest first , the first one older than the current is the previous version
+	var previous Collection
+	for _, col := range stale {
+		if isNewerVersion(current, col) {
+			previous = col
+			to = col.Name
+			break
+		}
+	}
+	if to == "" {
+		return from, "", fmt.Errorf("Typesense : no previous version of %s to roll back to", aliasName)
+	}
+
+	err = m.AliasCollection(&Alias{
+		Name:           aliasName,
+		CollectionName: to,
+	})
+	if err != nil {
+		return from, "", err
+	}
+	m.forgetAlias(aliasName)
+	return from, to, m.recordMigration(MigrationRecord{
+		Alias:          aliasName,
+		Action:         MigrationActionRollback,
+		FromCollection: from,
+		ToCollection:   to,
+		Diff:           DiffCollection(&current, &previous),
+	})
+}
@@ -0,0 +1,225 @@
+package typesense
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// newVersionTestServer : my_model -> my_model_2022-10-12_c with older versions , a newer one (ie left by a rollback)
+// and collections that only look like versions
+func newVersionTestServer(t *testing.T) *migrationTestServer {
+	server := newMigrationTestServer(t)
+	versions := map[string]int64{
+		"my_model_2022-10-10_a":    1,
+		"my_model_2022-10-11_b":    2,
+		"my_model_2022-10-12_c":    3,
+		"my_model_2022-10-13_d":    4,
+		"my_model_other":           5,
+		"my_model_2022-10-09":      6,
+		"my_model_x_2022-10-09_e":  7,
+		"other_model_2022-10-10_f": 8,
+	}
+	for name, createdAt := range versions {
+		server.addCollection(name)
+		server.collections[name].schema.CreatedAt = createdAt
+	}
+	server.aliases["my_model"] = "my_model_2022-10-12_c"
+	return server
+}
+
+func TestPruneVersions(t *testing.T) {
+	tests := []struct {
+		keep int
+		want []string
+	}{
+		{keep: 0, want: []string{"my_model_2022-10-13_d", "my_model_2022-10-11_b", "my_model_2022-10-10_a"}},
+		{keep: 1, want: []string{"my_model_2022-10-11_b", "my_model_2022-10-10_a"}},
+		{keep: 2, want: []string{"my_model_2022-10-10_a"}},
+		{keep: 3, want: nil},
+		{keep: 10, want: nil},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("keep %d", test.keep), func(t *testing.T) {
+			server := newVersionTestServer(t)
+			migration := NewManualMigration("key", server.URL, false)
+			before := len(server.collectionNames())
+
+			dryRun, err := migration.PruneVersionsDryRun("my_model", test.keep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(dryRun) != fmt.Sprint(test.want) || len(server.collectionNames()) != before {
+				t.Fatalf("PruneVersionsDryRun() = %v , want %v without deleting", dryRun, test.want)
+			}
+
+			deleted, err := migration.PruneVersions("my_model", test.keep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(deleted) != fmt.Sprint(test.want) {
+				t.Fatalf("PruneVersions() = %v , want %v", deleted, test.want)
+			}
+			remaining := server.collectionNames()
+			if len(remaining) != before-len(test.want) {
+				t.Fatalf("remaining collections = %v", remaining)
+			}
+			// the current target and the look alike collections are never deleted
+			sort.Strings(remaining)
+			for _, colName := range []string{"my_model_2022-10-12_c", "my_model_other", "my_model_2022-10-09", "my_model_x_2022-10-09_e", "other_model_2022-10-10_f"} {
+				if i := sort.SearchStrings(remaining, colName); i == len(remaining) || remaining[i] != colName {
+					t.Fatalf("%s was deleted , remaining = %v", colName, remaining)
+				}
+			}
+		})
+	}
+}
+
+func TestPruneVersionsSameCreatedAt(t *testing.T) {
+	server := newVersionTestServer(t)
+	// same creation time , the name (date then hash) decides
+	for _, colName := range []string{"my_model_2022-10-10_a", "my_model_2022-10-11_b", "my_model_2022-10-13_d"} {
+		server.collections[colName].schema.CreatedAt = 1
+	}
+	migration := NewManualMigration("key", server.URL, false)
+
+	got, err := migration.PruneVersionsDryRun("my_model", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"my_model_2022-10-11_b", "my_model_2022-10-10_a"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("PruneVersionsDryRun() = %v , want %v", got, want)
+	}
+}
+
+func TestPruneVersionsErrors(t *testing.T) {
+	server := newVersionTestServer(t)
+	migration := NewManualMigration("key", server.URL, false)
+	before := len(server.collectionNames())
+
+	_, err := migration.PruneVersions("my_model", -1)
+	if err == nil || !strings.Contains(err.Error(), "cannot keep -1 versions") {
+		t.Fatalf("PruneVersions(-1) = %v", err)
+	}
+	_, err = migration.PruneVersions("missing_model", 0)
+	if err == nil || !strings.Contains(err.Error(), "alias missing_model does not exist") {
+		t.Fatalf("PruneVersions(missing_model) = %v", err)
+	}
+	if len(server.collectionNames()) != before {
+		t.Fatalf("collections = %v", server.collectionNames())
+	}
+}
+
+func TestRollback(t *testing.T) {
+	server := newVersionTestServer(t)
+	migration := NewManualMigration("key", server.URL, false)
+
+	// the newer version (d) is skipped , rollback only goes back in time
+	wantSteps := [][2]string{
+		{"my_model_2022-10-12_c", "my_model_2022-10-11_b"},
+		{"my_model_2022-10-11_b", "my_model_2022-10-10_a"},
+	}
+	for _, want := range wantSteps {
+		from, to, err := migration.Rollback("my_model")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if from != want[0] || to != want[1] || server.aliases["my_model"] != want[1] {
+			t.Fatalf("Rollback() = %s -> %s , alias = %s , want %v", from, to, server.aliases["my_model"], want)
+		}
+	}
+
+	from, to, err := migration.Rollback("my_model")
+	if err == nil || !strings.Contains(err.Error(), "no previous version of my_model") {
+		t.Fatalf("Rollback() = %s -> %s , %v", from, to, err)
+	}
+	if from != "my_model_2022-10-10_a" || to != "" || server.aliases["my_model"] != "my_model_2022-10-10_a" {
+		t.Fatalf("Rollback() = %s -> %s , alias = %s", from, to, server.aliases["my_model"])
+	}
+	// nothing is deleted
+	if len(server.collectionNames()) != 8 {
+		t.Fatalf("collections = %v", server.collectionNames())
+	}
+}
+
+func TestRollbackSameCreatedAt(t *testing.T) {
+	server := newMigrationTestServer(t)
+	for _, colName := range []string{"my_model_2022-10-11_a", "my_model_2022-10-11_b", "my_model_2022-10-11_c", "my_model_2022-10-10_z"} {
+		server.addCollection(colName)
+		server.collections[colName].schema.CreatedAt = 1
+	}
+	server.aliases["my_model"] = "my_model_2022-10-11_b"
+	migration := NewManualMigration("key", server.URL, false)
+
+	// same creation time , the name decides : _c is newer , _a is the previous version
+	_, to, err := migration.Rollback("my_model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if to != "my_model_2022-10-11_a" {
+		t.Fatalf("Rollback() to %s , want my_model_2022-10-11_a", to)
+	}
+	_, to, err = migration.Rollback("my_model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if to != "my_model_2022-10-10_z" {
+		t.Fatalf("Rollback() to %s , want my_model_2022-10-10_z", to)
+	}
+}
+
+func TestRollbackErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(server *migrationTestServer)
+		wantErr string
+	}{
+		{
+			name:    "missing alias",
+			setup:   func(server *migrationTestServer) {},
+			wantErr: "alias my_model does not exist",
+		},
+		{
+			name: "missing aliased collection",
+			setup: func(server *migrationTestServer) {
+				server.aliases["my_model"] = "my_model_2022-10-12_c"
+			},
+			wantErr: "collection my_model_2022-10-12_c for alias my_model does not exist",
+		},
+		{
+			name: "only one version",
+			setup: func(server *migrationTestServer) {
+				server.addCollection("my_model_2022-10-12_c")
+				server.addCollection("my_model_other")
+				server.aliases["my_model"] = "my_model_2022-10-12_c"
+			},
+			wantErr: "no previous version of my_model",
+		},
+		{
+			name: "only newer versions",
+			setup: func(server *migrationTestServer) {
+				server.addCollection("my_model_2022-10-12_c")
+				server.addCollection("my_model_2022-10-13_d")
+				server.aliases["my_model"] = "my_model_2022-10-12_c"
+			},
+			wantErr: "no previous version of my_model",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newMigrationTestServer(t)
+			test.setup(server)
+			alias := server.aliases["my_model"]
+			migration := NewManualMigration("key", server.URL, false)
+
+			_, to, err := migration.Rollback("my_model")
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("Rollback() = %v , want %q", err, test.wantErr)
+			}
+			if to != "" || server.aliases["my_model"] != alias {
+				t.Fatalf("Rollback() to %q , alias = %q", to, server.aliases["my_model"])
+			}
+		})
+	}
+}
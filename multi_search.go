@@ -0,0 +1,271 @@
+package typesense
+
+import (
+	"encoding/json"
+	"errors"
+
+	http2 "github.com/baderkha/typesense/pkg/http"
+)
+
+// IMultiSearchClient : sends several searches (on different collections / models) in a single request
+type IMultiSearchClient interface {
+	// Perform : sends all the searches added to the multi search in one /multi_search request ,
+	// the typed results are read from the handles returned by AddSearch
+	//
+	// Example:
+	//			ms := typesense.NewMultiSearch()
+	//			products := typesense.AddSearch[Product](ms, typesense.NewSearchParams().AddSearchTerm("shoe").AddQueryBy("name"))
+	//			brands := typesense.AddSearch[Brand](ms, typesense.NewSearchParams().AddSearchTerm("shoe").AddQueryBy("name"))
+	//			err := client.MultiSearch().Perform(ms)
+	//			if err != nil {
+	//				log.Fatal(err)
+	//			}
+	//			productRes, err := products.Result() // typesense.SearchResult[Product]
+	//			brandRes, err := brands.Result() // typesense.SearchResult[Brand]
+	//
+	Perform(ms *MultiSearch) error
+	// Union : sends all the searches with union enabled , the hits of every search come back as one ranked list
+	// each tagged with the collection (and index of the search) it came from . decode the documents with
+	// DecodeUnionHit (per collection) or UnionResultAs (one type / map[string]interface{} for all of them)
+	//
+	// Example:
+	//			ms := typesense.NewMultiSearch().AddCommonParams(typesense.NewSearchParams().AddPage(1).AddPerPage(20))
+	//			typesense.AddSearch[User](ms, typesense.NewSearchParams().AddSearchTerm("ali").AddQueryBy("name"))
+	//			typesense.AddSearch[Team](ms, typesense.NewSearchParams().AddSearchTerm("ali").AddQueryBy("title"))
+	//			res, err := client.MultiSearch().Union(ms)
+	//			for _, hit := range res.Hits {
+	//				switch hit.Collection {
+	//				case "user":
+	//					user, err := typesense.DecodeUnionHit[User](hit)
+	//				case "team":
+	//					team, err := typesense.DecodeUnionHit[Team](hit)
+	//				}
+	//			}
+	//
+	Union(ms *MultiSearch) (UnionSearchResult[json.RawMessage], error)
+}
+
+// NewMultiSearchClient : create a new multi search client which sends many searches in one request
+func NewMultiSearchClient(apiKey string, host string, logging bool) IMultiSearchClient {
+	base := newBaseClient[any](apiKey, host, logging)
+	return &MultiSearchClient{
+		baseClient: base,
+	}
+}
+
+// MultiSearchClient : sends several searches (on different collections / models) in a single request
+type MultiSearchClient struct {
+	*baseClient[any]
+}
+
+// MultiSearch : the searches to send in one request , add searches with AddSearch / AddSearchToCollection
+type MultiSearch struct {
+	searches     []multiSearchQuery
+	commonParams *SearchParameters
+	results      []json.RawMessage
+}
+
+type multiSearchQuery struct {
+	colName string
+	params  *SearchParameters
+}
+
+// multiSearchError : error returned in place of a result for one of the searches
+type multiSearchError struct {
+	Code  int    `json:"code"`
+	Error string `json:"error"`
+}
+
+// NewMultiSearch : a new empty batch of searches
+func NewMultiSearch() *MultiSearch {
+	return &MultiSearch{}
+}
+
+// AddCommonParams : params applied to every search (sent as query params) , for union searches this is where
+// the page / per page of the merged list go
+func (ms *MultiSearch) AddCommonParams(s *SearchParameters) *MultiSearch {
+	ms.commonParams = s
+	return ms
+}
+
+// AddSearch : adds a search on the collection (or alias) of the model T , returns a handle to read the typed result
+func AddSearch[T any](ms *MultiSearch, s *SearchParameters) *MultiSearchHandle[T] {
+	return AddSearchToCollection[T](ms, modelCollectionName[T](), s)
+}
+
+// AddSearchToCollection : adds a search on a specific collection (or alias) , returns a handle to read the typed result
+func AddSearchToCollection[T any](ms *MultiSearch, colName string, s *SearchParameters) *MultiSearchHandle[T] {
+	ms.searches = append(ms.searches, multiSearchQuery{
+		colName: colName,
+		params:  s,
+	})
+	return &MultiSearchHandle[T]{
+		ms:    ms,
+		index: len(ms.searches) - 1,
+	}
+}
+
+// queryParams : the common params of the /multi_search request
+func (ms *MultiSearch) queryParams() (map[string]string, error) {
+	if ms.commonParams == nil {
+		return nil, nil
+	}
+	if ms.commonParams.err != nil {
+		return nil, ms.commonParams.err
+	}
+	return toQueryParams(ms.commonParams)
+}
+
+// body : the /multi_search request body , each search is its params + the collection
+func (ms *MultiSearch) body() (map[string]interface{}, error) {
+	var searches []map[string]interface{}
+	for _, search := range ms.searches {
+		if search.params != nil && search.params.err != nil {
+			return nil, search.params.err
+		}
+		var searchBody map[string]interface{}
+		b, err := json.Marshal(search.params)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(b, &searchBody)
+		if err != nil {
+			return nil, err
+		}
+		if searchBody == nil {
+			searchBody = make(map[string]interface{})
+		}
+		searchBody["collection"] = search.colName
+		searches = append(searches, searchBody)
+	}
+	return map[string]interface{}{"searches": searches}, nil
+}
+
+// MultiSearchHandle : reads the typed result of one of the searches once the multi search is performed
+type MultiSearchHandle[T any] struct {
+	ms    *MultiSearch
+	index int
+}
+
+// Result : the result of the search , errors if the multi search wasn't performed or if this search failed
+func (h *MultiSearchHandle[T]) Result() (SearchResult[T], error) {
+	var res SearchResult[T]
+	if h.index >= len(h.ms.results) {
+		return res, errors.New("Typesense : multi search was not performed yet")
+	}
+	raw := h.ms.results[h.index]
+
+	var searchErr multiSearchError
+	_ = json.Unmarshal(raw, &searchErr)
+	if searchErr.Error != "" {
+		return res, &SearchError{
+			StatusCode: searchErr.Code,
+			Message:    searchErr.Error,
+			Collection: h.ms.searches[h.index].colName,
+		}
+	}
+	err := json.Unmarshal(raw, &res)
+	return res, err
+}
+
+// Perform : sends all the searches added to the multi search in one /multi_search request ,
+// the typed results are read from the handles returned by AddSearch
+func (m *MultiSearchClient) Perform(ms *MultiSearch) error {
+	body, err := ms.body()
+	if err != nil {
+		return err
+	}
+	params, err := ms.queryParams()
+	if err != nil {
+		return err
+	}
+	var multiRes struct {
+		Results []json.RawMessage `json:"results"`
+	}
+	res, err := m.Req().
+		SetQueryParams(params).
+		SetBody(body).
+		SetResult(&multiRes).
+		Post("/multi_search")
+	if err != nil {
+		return &SearchError{Err: err}
+	} else if !http2.StatusIsSuccess(res.StatusCode()) {
+		return newSearchError("", res.StatusCode(), res.Body())
+	}
+	ms.results = multiRes.Results
+	return nil
+}
+
+// UnionHit : a hit of a union search , tagged with the collection and the index of the search it came from
+type UnionHit[T any] struct {
+	Hit[T]
+	Collection  string `json:"collection"`
+	SearchIndex int    `json:"search_index"`
+}
+
+// UnionSearchResult : the merged result of a union search
+type UnionSearchResult[T any] struct {
+	SearchResultBase
+	Hits []UnionHit[T] `json:"hits"`
+}
+
+// GetDocuments : returns the documents in the merged order
+func (s *UnionSearchResult[T]) GetDocuments() []*T {
+	var modelRecords []*T
+	for i := range s.Hits {
+		modelRecords = append(modelRecords, &s.Hits[i].Document)
+	}
+	return modelRecords
+}
+
+// DecodeUnionHit : decodes the document of a union hit to the model of its collection
+func DecodeUnionHit[T any](hit UnionHit[json.RawMessage]) (T, error) {
+	var doc T
+	err := json.Unmarshal(hit.Document, &doc)
+	return doc, err
+}
+
+// UnionResultAs : decodes every document of a union search to T , use map[string]interface{} or your own
+// sum type (with an UnmarshalJSON) when the collections have different models
+func UnionResultAs[T any](res UnionSearchResult[json.RawMessage]) (UnionSearchResult[T], error) {
+	typedRes := UnionSearchResult[T]{SearchResultBase: res.SearchResultBase}
+	for _, hit := range res.Hits {
+		// round trip the hit so every hit field carries over with the typed document
+		b, err := json.Marshal(hit)
+		if err != nil {
+			return typedRes, err
+		}
+		var typedHit UnionHit[T]
+		err = json.Unmarshal(b, &typedHit)
+		if err != nil {
+			return typedRes, err
+		}
+		typedRes.Hits = append(typedRes.Hits, typedHit)
+	}
+	return typedRes, nil
+}
+
+// Union : sends all the searches with union enabled , the hits of every search come back as one ranked list
+func (m *MultiSearchClient) Union(ms *MultiSearch) (UnionSearchResult[json.RawMessage], error) {
+	var unionRes UnionSearchResult[json.RawMessage]
+	body, err := ms.body()
+	if err != nil {
+		return unionRes, err
+	}
+	body["union"] = true
+	params, err := ms.queryParams()
+	if err != nil {
+		return unionRes, err
+	}
+	res, err := m.Req().
+		SetQueryParams(params).
+		SetBody(body).
+		SetResult(&unionRes).
+		Post("/multi_search")
+	if err != nil {
+		return unionRes, &SearchError{Err: err}
+	} else if !http2.StatusIsSuccess(res.StatusCode()) {
+		return unionRes, newSearchError("", res.StatusCode(), res.Body())
+	}
+	return unionRes, nil
+}
@@ -0,0 +1,244 @@
+package typesense
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type searchTestBrand struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Country string `json:"country"`
+}
+
+// multiSearchTestResponse : a /multi_search response , the second search failed
+const multiSearchTestResponse = `{
+  "results": [
+    {
+      "facet_counts": [],
+      "found": 1,
+      "hits": [
+        {
+          "document": {"brand": "Nike", "id": "1", "name": "Nike running shoe", "tags": ["running"]},
+          "highlight": {"name": {"matched_tokens": ["shoe"], "snippet": "Nike running <mark>shoe</mark>"}},
+          "highlights": [{"field": "name", "matched_tokens": ["shoe"], "snippet": "Nike running <mark>shoe</mark>"}],
+          "text_match": 578730123365187705
+        }
+      ],
+      "out_of": 3,
+      "page": 1,
+      "request_params": {"collection_name": "search_test_product", "first_q": "shoe", "per_page": 10, "q": "shoe"},
+      "search_cutoff": false,
+      "search_time_ms": 0
+    },
+    {"code": 404, "error": "Not found."},
+    {
+      "facet_counts": [],
+      "found": 1,
+      "hits": [
+        {
+          "document": {"country": "US", "id": "b1", "name": "Nike"},
+          "highlights": [],
+          "text_match": 100
+        }
+      ],
+      "out_of": 1,
+      "page": 1,
+      "request_params": {"collection_name": "search_test_brand", "first_q": "nike", "per_page": 10, "q": "nike"},
+      "search_cutoff": false,
+      "search_time_ms": 0
+    }
+  ]
+}`
+
+// unionSearchTestResponse : a /multi_search response with union enabled
+const unionSearchTestResponse = `{
+  "found": 2,
+  "hits": [
+    {
+      "collection": "search_test_brand",
+      "document": {"country": "US", "id": "b1", "name": "Nike"},
+      "highlight": {"name": {"matched_tokens": ["Nike"], "snippet": "<mark>Nike</mark>"}},
+      "highlights": [{"field": "name", "matched_tokens": ["Nike"], "snippet": "<mark>Nike</mark>"}],
+      "search_index": 1,
+      "text_match": 578730123365711993
+    },
+    {
+      "collection": "search_test_product",
+      "document": {"brand": "Nike", "id": "1", "name": "Nike running shoe", "tags": ["nike", "running"]},
+      "highlight": {},
+      "highlights": [{"field": "tags", "indices": [0], "matched_tokens": [["nike"]], "snippets": ["<mark>nike</mark>"]}],
+      "search_index": 0,
+      "text_match": 578730123365187705
+    }
+  ],
+  "out_of": 4,
+  "page": 1,
+  "search_cutoff": false,
+  "search_time_ms": 1,
+  "union_request_params": [
+    {"collection": "search_test_product", "found": 1, "per_page": 10, "q": "nike"},
+    {"collection": "search_test_brand", "found": 1, "per_page": 10, "q": "nike"}
+  ]
+}`
+
+type multiSearchTestRequest struct {
+	Query string
+	Body  struct {
+		Union    bool                     `json:"union"`
+		Searches []map[string]interface{} `json:"searches"`
+	}
+}
+
+func newMultiSearchTestServer(t *testing.T, status int, response string, req *multiSearchTestRequest) IMultiSearchClient {
+	server := newSearchTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/multi_search" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		req.Query = r.URL.RawQuery
+		err := json.NewDecoder(r.Body).Decode(&req.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	})
+	return NewMultiSearchClient("key", server.URL, false)
+}
+
+func TestMultiSearchPerform(t *testing.T) {
+	var req multiSearchTestRequest
+	client := newMultiSearchTestServer(t, http.StatusOK, multiSearchTestResponse, &req)
+
+	ms := NewMultiSearch().AddCommonParams(NewSearchParams().AddPerPage(10))
+	products := AddSearch[searchTestProduct](ms, NewSearchParams().AddSearchTerm("shoe").AddQueryBy("name"))
+	missing := AddSearchToCollection[searchTestProduct](ms, "missing", NewSearchParams().AddSearchTerm("shoe"))
+	brands := AddSearch[searchTestBrand](ms, NewSearchParams().AddSearchTerm("nike").AddQueryBy("name"))
+
+	if _, err := products.Result(); err == nil {
+		t.Fatal("Result() before Perform should error")
+	}
+	err := client.Perform(ms)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.Query != "per_page=10&q=%2A" || req.Body.Union || len(req.Body.Searches) != 3 {
+		t.Fatalf("request = %+v", req)
+	}
+	wantSearches := []map[string]interface{}{
+		{"collection": "search_test_product", "q": "shoe", "query_by": "name"},
+		{"collection": "missing", "q": "shoe"},
+		{"collection": "search_test_brand", "q": "nike", "query_by": "name"},
+	}
+	for i, want := range wantSearches {
+		for key, value := range want {
+			if req.Body.Searches[i][key] != value {
+				t.Fatalf("search %d = %v , want %s = %v", i, req.Body.Searches[i], key, value)
+			}
+		}
+	}
+
+	productRes, err := products.Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if productRes.Found != 1 || productRes.Hits[0].Document.Name != "Nike running shoe" ||
+		len(productRes.Hits[0].Highlights) != 1 || productRes.Hits[0].Highlights[0].Field != "name" {
+		t.Fatalf("products = %+v", productRes)
+	}
+
+	_, err = missing.Result()
+	var searchErr *SearchError
+	if !errors.As(err, &searchErr) || !searchErr.IsNotFound() || searchErr.Message != "Not found." || searchErr.Collection != "missing" {
+		t.Fatalf("missing = %v", err)
+	}
+
+	brandRes, err := brands.Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if docs := brandRes.GetDocuments(); len(docs) != 1 || *docs[0] != (searchTestBrand{ID: "b1", Name: "Nike", Country: "US"}) {
+		t.Fatalf("brands = %+v", brandRes)
+	}
+}
+
+func TestMultiSearchErrors(t *testing.T) {
+	var req multiSearchTestRequest
+	client := newMultiSearchTestServer(t, http.StatusBadRequest, `{"message": "Number of multi searches exceeds `+"`limit_multi_searches`"+` parameter."}`, &req)
+
+	ms := NewMultiSearch()
+	AddSearch[searchTestProduct](ms, NewSearchParams().AddSearchTerm("shoe"))
+	err := client.Perform(ms)
+	var searchErr *SearchError
+	if !errors.As(err, &searchErr) || !searchErr.IsInvalidQuery() {
+		t.Fatalf("Perform() = %v", err)
+	}
+	_, err = client.Union(ms)
+	if !errors.As(err, &searchErr) || !searchErr.IsInvalidQuery() {
+		t.Fatalf("Union() = %v", err)
+	}
+
+	// params errors are returned before sending anything
+	req = multiSearchTestRequest{}
+	ms = NewMultiSearch()
+	AddSearch[searchTestProduct](ms, NewSearchParams().AddSortByField(Field[searchTestProduct]("Missing"), SortAsc))
+	if err := client.Perform(ms); err == nil || req.Body.Searches != nil {
+		t.Fatalf("Perform() = %v , request = %+v", err, req)
+	}
+}
+
+func TestMultiSearchUnion(t *testing.T) {
+	var req multiSearchTestRequest
+	client := newMultiSearchTestServer(t, http.StatusOK, unionSearchTestResponse, &req)
+
+	ms := NewMultiSearch().AddCommonParams(NewSearchParams().AddPage(1).AddPerPage(10))
+	AddSearch[searchTestProduct](ms, NewSearchParams().AddSearchTerm("nike").AddQueryBy("name,tags"))
+	AddSearch[searchTestBrand](ms, NewSearchParams().AddSearchTerm("nike").AddQueryBy("name"))
+	res, err := client.Union(ms)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !req.Body.Union || len(req.Body.Searches) != 2 || req.Query != "page=1&per_page=10&q=%2A" {
+		t.Fatalf("request = %+v", req)
+	}
+	if res.Found != 2 || res.OutOf != 4 || len(res.Hits) != 2 {
+		t.Fatalf("result = %+v", res)
+	}
+	if res.Hits[0].Collection != "search_test_brand" || res.Hits[0].SearchIndex != 1 ||
+		res.Hits[1].Collection != "search_test_product" || res.Hits[1].SearchIndex != 0 {
+		t.Fatalf("hits = %+v", res.Hits)
+	}
+
+	brand, err := DecodeUnionHit[searchTestBrand](res.Hits[0])
+	if err != nil || brand != (searchTestBrand{ID: "b1", Name: "Nike", Country: "US"}) {
+		t.Fatalf("DecodeUnionHit() = %+v , %v", brand, err)
+	}
+	product, err := DecodeUnionHit[searchTestProduct](res.Hits[1])
+	if err != nil || !reflect.DeepEqual(product, searchTestProduct{ID: "1", Name: "Nike running shoe", Brand: "Nike", Tags: []string{"nike", "running"}}) {
+		t.Fatalf("DecodeUnionHit() = %+v , %v", product, err)
+	}
+
+	anyRes, err := UnionResultAs[map[string]interface{}](res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anyRes.Found != 2 || len(anyRes.Hits) != 2 || anyRes.Hits[0].Document["country"] != "US" || anyRes.Hits[1].Document["brand"] != "Nike" {
+		t.Fatalf("UnionResultAs() = %+v", anyRes)
+	}
+	// every hit field carries over with the typed document
+	for i, hit := range anyRes.Hits {
+		raw := res.Hits[i]
+		if hit.Collection != raw.Collection || hit.SearchIndex != raw.SearchIndex || hit.TextMatch != raw.TextMatch ||
+			!reflect.DeepEqual(hit.Highlights, raw.Highlights) || len(hit.Highlight) != len(raw.Highlight) {
+			t.Fatalf("hit %d = %+v , want %+v", i, hit, raw)
+		}
+	}
+	if tokens := anyRes.Hits[1].Highlights[0].ArrayMatchedTokens; !reflect.DeepEqual(tokens, [][]string{{"nike"}}) {
+		t.Fatalf("array matched tokens = %v", tokens)
+	}
+}
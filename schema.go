@@ -0,0 +1,343 @@
+package typesense
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baderkha/typesense/pkg/conditional"
+	"github.com/baderkha/typesense/pkg/stringutil"
+	"github.com/baderkha/typesense/types"
+)
+
+// modelSchema : typesense fields built from a model struct and its tags
+type modelSchema struct {
+	Fields      []CollectionField
+	DefaultSort string
+	HasNested   bool
+
+	// visiting : nested struct types being walked , stops self referencing models
+	visiting map[reflect.Type]bool
+}
+
+// newModelSchema : reads the exported fields of a struct type (embedded structs are flattened like encoding/json does)
+//
+// nested struct fields map to object / object[] fields , their own fields are flattened by typesense .
+// nested fields with tsense tags are added as explicit parent.child fields so they can be sorted / faceted on
+func newModelSchema(modelType reflect.Type) (*modelSchema, error) {
+	modelType = derefType(modelType)
+	if modelType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Typesense : %s is not a struct , cannot build a schema from it", modelType)
+	}
+	schema := modelSchema{visiting: map[reflect.Type]bool{modelType: true}}
+	return &schema, schema.addStructFields(modelType, nil)
+}
+
+// field : the schema field with the typesense name
+func (s *modelSchema) field(name string) (CollectionField, bool) {
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return CollectionField{}, false
+}
+
+// addStructFields : adds the fields of the struct , parent is the object field of a nested struct (nil for the model)
+func (s *modelSchema) addStructFields(structType reflect.Type, parent *CollectionField) error {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name, omitEmpty, skip := schemaFieldName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && !hasJSONName(field) && derefType(field.Type).Kind() == reflect.Struct {
+			err := s.addStructFields(derefType(field.Type), parent)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		// id is managed by typesense , it can't be part of the schema .
+		// embedded structs with a json name are encoded as a field even when their type is unexported
+		isEmbeddedStruct := field.Anonymous && derefType(field.Type).Kind() == reflect.Struct
+		if (!field.IsExported() && !isEmbeddedStruct) || (parent == nil && name == "id") {
+			continue
+		}
+
+		sortVal := field.Tag.Get(TagSort)
+		indexVal := field.Tag.Get(TagIndex)
+		requiredVal := field.Tag.Get(TagRequired)
+		facetVal := field.Tag.Get(TagFacet)
+		overrideTypeVal := field.Tag.Get(TagTypeOverride)
+		defaultSortVal := field.Tag.Get(TagDefaultSort)
+
+		tType := overrideTypeVal
+		var isPointer bool
+		if tType == "" {
+			goType, optional, err := golangToTypesenseType(field.Type)
+			if err != nil {
+				return fmt.Errorf("%s for %s field", err.Error(), name)
+			}
+			tType = goType
+			isPointer = optional
+		}
+		if parent != nil {
+			name = parent.Name + "." + name
+			// every field of an object array is an array
+			if parent.Type == "object[]" && !strings.HasSuffix(tType, "[]") {
+				tType += "[]"
+			}
+		}
+		if strings.HasPrefix(tType, "object") {
+			s.HasNested = true
+		}
+
+		if defaultSortVal != "" {
+			if parent != nil {
+				return fmt.Errorf("Typesense : default sort field %s must be a top level field", name)
+			}
+			if s.DefaultSort != "" {
+				return fmt.Errorf("Typesense : You cannot have more than 1 default sort field")
+			}
+			s.DefaultSort = name
+			requiredVal = "1"
+			indexVal = "1"
+		}
+
+		colField := CollectionField{
+			Facet:    facetVal != "",
+			Index:    indexVal != "",
+			Optional: requiredVal == "" || ((omitEmpty || isPointer) && defaultSortVal == ""),
+			Sort:     sortVal != "",
+			Name:     name,
+			Type:     tType,
+		}
+		// the children of an optional object can be missing
+		if parent != nil && parent.Optional {
+			colField.Optional = true
+		}
+		err := addVectorOptions(&colField, field)
+		if err != nil {
+			return err
+		}
+		// untagged nested fields are flattened by typesense on their own
+		if parent == nil || hasSchemaTags(field) {
+			s.Fields = append(s.Fields, colField)
+		}
+
+		childType := nestedStructType(field.Type)
+		if overrideTypeVal == "" && strings.HasPrefix(tType, "object") && childType != nil && !s.visiting[childType] {
+			s.visiting[childType] = true
+			err = s.addStructFields(childType, &colField)
+			delete(s.visiting, childType)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasSchemaTags : true if the field has one of the tsense tags that change its schema
+func hasSchemaTags(field reflect.StructField) bool {
+	for _, tag := range []string{TagSort, TagIndex, TagRequired, TagFacet, TagTypeOverride, TagNumDim, TagEmbedFrom} {
+		if field.Tag.Get(tag) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedStructType : the struct of an object / object[] field , nil for maps
+func nestedStructType(t reflect.Type) reflect.Type {
+	t = derefType(t)
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = derefType(t.Elem())
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// addVectorOptions : reads the vector / embedding tags of a float[] field
+func addVectorOptions(colField *CollectionField, field reflect.StructField) error {
+	numDimVal := field.Tag.Get(TagNumDim)
+	embedFromVal := field.Tag.Get(TagEmbedFrom)
+	embedModelVal := field.Tag.Get(TagEmbedModel)
+	vecDistVal := field.Tag.Get(TagVecDist)
+
+	if numDimVal != "" {
+		numDim, err := strconv.Atoi(numDimVal)
+		if err != nil || numDim <= 0 {
+			return fmt.Errorf("Typesense : invalid %s value '%s' for %s field", TagNumDim, numDimVal, colField.Name)
+		}
+		colField.NumDim = numDim
+	}
+	if embedFromVal != "" {
+		if embedModelVal == "" {
+			return fmt.Errorf("Typesense : %s field embeds from '%s' but has no %s", colField.Name, embedFromVal, TagEmbedModel)
+		}
+		colField.Embed = &FieldEmbed{
+			From:        strings.Split(embedFromVal, ","),
+			ModelConfig: embedModelConfig(embedModelVal),
+		}
+	}
+	if colField.NumDim == 0 && colField.Embed == nil {
+		if vecDistVal != "" {
+			return fmt.Errorf("Typesense : %s field has a %s but is not a vector , add %s or %s", colField.Name, TagVecDist, TagNumDim, TagEmbedFrom)
+		}
+		return nil
+	}
+	if colField.Type != "float[]" {
+		return fmt.Errorf("Typesense : vector field %s must be a float slice , got %s", colField.Name, colField.Type)
+	}
+	if vecDistVal != "" && vecDistVal != "cosine" && vecDistVal != "ip" {
+		return fmt.Errorf("Typesense : invalid %s value '%s' for %s field , use cosine or ip", TagVecDist, vecDistVal, colField.Name)
+	}
+	colField.VecDist = vecDistVal
+	// a vector field that isn't indexed can't be searched
+	colField.Index = true
+	return nil
+}
+
+var (
+	embedModelConfigsMu sync.RWMutex
+	embedModelConfigs   = make(map[string]EmbedModelConfig)
+)
+
+// SetEmbedModelConfig : configures an embedding model referenced by the tsense_embed_model tag (api keys , urls ..etc)
+//
+// Example :
+//			typesense.SetEmbedModelConfig(typesense.EmbedModelConfig{
+//				ModelName: "openai/text-embedding-ada-002",
+//				APIKey:    os.Getenv("OPENAI_API_KEY"),
+//			})
+//
+func SetEmbedModelConfig(config EmbedModelConfig) {
+	embedModelConfigsMu.Lock()
+	defer embedModelConfigsMu.Unlock()
+	embedModelConfigs[config.ModelName] = config
+}
+
+func embedModelConfig(modelName string) EmbedModelConfig {
+	embedModelConfigsMu.RLock()
+	defer embedModelConfigsMu.RUnlock()
+	config, ok := embedModelConfigs[modelName]
+	if !ok {
+		return EmbedModelConfig{ModelName: modelName}
+	}
+	return config
+}
+
+// schemaFieldName : the typesense name of a struct field , the json name by default falling back to the db tag
+// and then the snake cased go name
+func schemaFieldName(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	jsonTag := field.Tag.Get("json")
+	if jsonTag == "-" {
+		return "", false, true
+	}
+	jsonParts := strings.Split(jsonTag, ",")
+	for _, option := range jsonParts[1:] {
+		omitEmpty = omitEmpty || option == "omitempty"
+	}
+	name = jsonParts[0]
+	name = conditional.Ternary(name != "", name, field.Tag.Get("db"))
+	name = conditional.Ternary(name != "", name, stringutil.Underscore(field.Name))
+	return name, omitEmpty, false
+}
+
+func hasJSONName(field reflect.StructField) bool {
+	return strings.Split(field.Tag.Get("json"), ",")[0] != ""
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+var (
+	typeMappingsMu sync.RWMutex
+	// typeMappings : go types with a fixed typesense type , checked before the type's kind
+	typeMappings = map[reflect.Type]string{
+		reflect.TypeOf(time.Time{}):       "int64",
+		reflect.TypeOf(types.Timestamp{}): "int64",
+		reflect.TypeOf(types.GeoPoint{}):  "geopoint",
+	}
+)
+
+// RegisterType : maps one of your own go types to a typesense type when building the collection schema .
+// registered types take precedence over the default mapping (also as slice elements , ie []V -> "<type>[]")
+//
+// Example :
+//			// your type
+//			type Money struct {
+//				Cents int64
+//			}
+//			// serialized as a float by your MarshalJSON
+//			typesense.RegisterType[Money]("float")
+//
+func RegisterType[V any](typesenseType string) {
+	typeMappingsMu.Lock()
+	defer typeMappingsMu.Unlock()
+	typeMappings[reflect.TypeOf((*V)(nil)).Elem()] = typesenseType
+}
+
+func registeredType(t reflect.Type) (string, bool) {
+	typeMappingsMu.RLock()
+	defer typeMappingsMu.RUnlock()
+	typ, ok := typeMappings[t]
+	return typ, ok
+}
+
+// golangToTypesenseType : maps a go type to a typesense type , pointers are mapped to their element as optional fields
+//
+// ints that fit in 32 bits map to int32 , the rest to int64 . structs and maps with string keys map to object
+func golangToTypesenseType(t reflect.Type) (typ string, optional bool, err error) {
+	if t.Kind() == reflect.Ptr {
+		typ, _, err := golangToTypesenseType(t.Elem())
+		return typ, true, err
+	}
+	if typ, ok := registeredType(t); ok {
+		return typ, false, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool", false, nil
+	case reflect.String:
+		return "string", false, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return "int32", false, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "int64", false, nil
+	case reflect.Float32, reflect.Float64:
+		return "float", false, nil
+	case reflect.Struct:
+		return "object", false, nil
+	case reflect.Map:
+		if t.Key().Kind() == reflect.String {
+			return "object", false, nil
+		}
+	case reflect.Slice, reflect.Array:
+		// []byte is encoded as a base64 string
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return "string", false, nil
+		}
+		elemType, _, err := golangToTypesenseType(t.Elem())
+		if err != nil {
+			return "", false, err
+		}
+		if strings.HasSuffix(elemType, "[]") {
+			return "", false, fmt.Errorf("Typesense : Unsupported nested array type %s", t)
+		}
+		return elemType + "[]", false, nil
+	}
+	return "", false, fmt.Errorf("Typesense : Unsupported type %s", t)
+}
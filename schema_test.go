@@ -0,0 +1,377 @@
+package typesense
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baderkha/typesense/types"
+)
+
+type schemaTestNaming struct {
+	ID          string `json:"id"`
+	JSONName    string `json:"json_name" db:"ignored"`
+	DBName      string `db:"db_name"`
+	GoName      string
+	OptionsOnly string `json:",omitempty" db:"from_db"`
+	Skipped     string `json:"-"`
+	unexported  string
+}
+
+type schemaTestRequired struct {
+	Name  string   `json:"name" tsense_required:"1"`
+	Nick  string   `json:"nick,omitempty" tsense_required:"1"`
+	Age   *int     `json:"age" tsense_required:"1"`
+	Bio   string   `json:"bio"`
+	Rank  int      `json:"rank,omitempty" tsense_default_sort:"1"`
+	Tags  []string `json:"tags" tsense_facet:"1" tsense_sort:"1" tsense_index:"1"`
+	Label string   `json:"label" tsense_type:"string*"`
+}
+
+type schemaTestBase struct {
+	ID        string `json:"id"`
+	CreatedAt int64  `json:"created_at" tsense_required:"1"`
+}
+
+type schemaTestAudit struct {
+	UpdatedBy string `json:"updated_by"`
+}
+
+type schemaTestAddress struct {
+	City string `json:"city"`
+}
+
+type schemaTestEmbedded struct {
+	schemaTestBase
+	*schemaTestAudit
+	schemaTestAddress `json:"address"`
+	Name              string `json:"name"`
+}
+
+type schemaTestNested struct {
+	Address  schemaTestAddress      `json:"address"`
+	Previous []schemaTestAddress    `json:"previous"`
+	Attrs    map[string]interface{} `json:"attrs"`
+	Manager  *schemaTestAddress     `json:"manager"`
+}
+
+type schemaTestGeo struct {
+	Country string `json:"country" tsense_facet:"1" tsense_required:"1"`
+}
+
+type schemaTestLocation struct {
+	City string        `json:"city" tsense_facet:"1" tsense_sort:"1"`
+	Zip  string        `json:"zip"`
+	Geo  schemaTestGeo `json:"geo"`
+}
+
+type schemaTestTaggedNested struct {
+	Home    schemaTestLocation      `json:"home" tsense_required:"1"`
+	Offices []schemaTestLocation    `json:"offices"`
+	Parent  *schemaTestTaggedNested `json:"parent"`
+	Labels  map[string]string       `json:"labels"`
+}
+
+type schemaTestNestedDefaultSort struct {
+	Inner struct {
+		Rank int `json:"rank" tsense_default_sort:"1"`
+	} `json:"inner"`
+}
+
+type schemaTestTwoDefaultSorts struct {
+	A int `json:"a" tsense_default_sort:"1"`
+	B int `json:"b" tsense_default_sort:"1"`
+}
+
+type schemaTestUnsupported struct {
+	Updates chan int `json:"updates"`
+}
+
+func TestNewModelSchema(t *testing.T) {
+	tests := []struct {
+		name            string
+		model           interface{}
+		wantFields      []CollectionField
+		wantDefaultSort string
+		wantNested      bool
+	}{
+		{
+			name:  "naming",
+			model: schemaTestNaming{},
+			wantFields: []CollectionField{
+				{Name: "json_name", Type: "string", Optional: true},
+				{Name: "db_name", Type: "string", Optional: true},
+				{Name: "go_name", Type: "string", Optional: true},
+				{Name: "from_db", Type: "string", Optional: true},
+			},
+		},
+		{
+			name:  "required , optional and tags",
+			model: &schemaTestRequired{},
+			wantFields: []CollectionField{
+				{Name: "name", Type: "string"},
+				{Name: "nick", Type: "string", Optional: true},
+				{Name: "age", Type: "int64", Optional: true},
+				{Name: "bio", Type: "string", Optional: true},
+				{Name: "rank", Type: "int64", Index: true},
+				{Name: "tags", Type: "string[]", Optional: true, Facet: true, Sort: true, Index: true},
+				{Name: "label", Type: "string*", Optional: true},
+			},
+			wantDefaultSort: "rank",
+		},
+		{
+			name:  "embedded structs are flattened",
+			model: schemaTestEmbedded{},
+			wantFields: []CollectionField{
+				{Name: "created_at", Type: "int64"},
+				{Name: "updated_by", Type: "string", Optional: true},
+				{Name: "address", Type: "object", Optional: true},
+				{Name: "name", Type: "string", Optional: true},
+			},
+			wantNested: true,
+		},
+		{
+			name:  "nested structs",
+			model: schemaTestNested{},
+			wantFields: []CollectionField{
+				{Name: "address", Type: "object", Optional: true},
+				{Name: "previous", Type: "object[]", Optional: true},
+				{Name: "attrs", Type: "object", Optional: true},
+				{Name: "manager", Type: "object", Optional: true},
+			},
+			wantNested: true,
+		},
+		{
+			name:  "tagged nested fields",
+			model: schemaTestTaggedNested{},
+			wantFields: []CollectionField{
+				{Name: "home", Type: "object"},
+				{Name: "home.city", Type: "string", Optional: true, Facet: true, Sort: true},
+				{Name: "home.geo.country", Type: "string", Optional: true, Facet: true},
+				{Name: "offices", Type: "object[]", Optional: true},
+				{Name: "offices.city", Type: "string[]", Optional: true, Facet: true, Sort: true},
+				{Name: "offices.geo.country", Type: "string[]", Optional: true, Facet: true},
+				{Name: "parent", Type: "object", Optional: true},
+				{Name: "labels", Type: "object", Optional: true},
+			},
+			wantNested: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema, err := newModelSchema(reflect.TypeOf(test.model))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(schema.Fields, test.wantFields) {
+				t.Fatalf("fields =\n%+v\nwant\n%+v", schema.Fields, test.wantFields)
+			}
+			if schema.DefaultSort != test.wantDefaultSort || schema.HasNested != test.wantNested {
+				t.Fatalf("default sort = %q , nested = %v", schema.DefaultSort, schema.HasNested)
+			}
+		})
+	}
+}
+
+func TestNewModelSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   interface{}
+		wantErr string
+	}{
+		{name: "not a struct", model: "", wantErr: "is not a struct"},
+		{name: "two default sorts", model: schemaTestTwoDefaultSorts{}, wantErr: "more than 1 default sort"},
+		{name: "nested default sort", model: schemaTestNestedDefaultSort{}, wantErr: "inner.rank must be a top level field"},
+		{name: "unsupported type", model: schemaTestUnsupported{}, wantErr: "for updates field"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := newModelSchema(reflect.TypeOf(test.model))
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("err = %v , want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestModelToCollection(t *testing.T) {
+	col, err := NewModelMigration[schemaTestNested]("key", "http://localhost:8108", false).ModelToCollection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if col.Name != "schema_test_nested" || !col.EnableNestedFields || len(col.Fields) != 4 || col.DefaultSortingField != "" {
+		t.Fatalf("collection = %+v", col)
+	}
+
+	col, err = NewModelMigration[schemaTestRequired]("key", "http://localhost:8108", false).ModelToCollection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if col.EnableNestedFields || col.DefaultSortingField != "rank" {
+		t.Fatalf("collection = %+v", col)
+	}
+}
+
+type schemaTestMoney struct {
+	Cents int64
+}
+
+type schemaTestStatus int
+
+func TestGolangToTypesenseType(t *testing.T) {
+	RegisterType[schemaTestMoney]("float")
+	RegisterType[schemaTestStatus]("string")
+	t.Cleanup(func() {
+		typeMappingsMu.Lock()
+		defer typeMappingsMu.Unlock()
+		delete(typeMappings, reflect.TypeOf(schemaTestMoney{}))
+		delete(typeMappings, reflect.TypeOf(schemaTestStatus(0)))
+	})
+
+	tests := []struct {
+		name         string
+		value        interface{}
+		wantType     string
+		wantOptional bool
+	}{
+		{name: "bool", value: false, wantType: "bool"},
+		{name: "string", value: "", wantType: "string"},
+		{name: "int8", value: int8(0), wantType: "int32"},
+		{name: "int16", value: int16(0), wantType: "int32"},
+		{name: "int32", value: int32(0), wantType: "int32"},
+		{name: "int", value: 0, wantType: "int64"},
+		{name: "int64", value: int64(0), wantType: "int64"},
+		{name: "uint8", value: uint8(0), wantType: "int32"},
+		{name: "uint16", value: uint16(0), wantType: "int32"},
+		{name: "uint32", value: uint32(0), wantType: "int64"},
+		{name: "uint", value: uint(0), wantType: "int64"},
+		{name: "uint64", value: uint64(0), wantType: "int64"},
+		{name: "float32", value: float32(0), wantType: "float"},
+		{name: "float64", value: float64(0), wantType: "float"},
+		{name: "pointer", value: new(string), wantType: "string", wantOptional: true},
+		{name: "pointer to pointer", value: new(*int32), wantType: "int32", wantOptional: true},
+		{name: "bytes", value: []byte{}, wantType: "string"},
+		{name: "string slice", value: []string{}, wantType: "string[]"},
+		{name: "int32 slice", value: []int32{}, wantType: "int32[]"},
+		{name: "int64 slice", value: []int{}, wantType: "int64[]"},
+		{name: "float slice", value: []float32{}, wantType: "float[]"},
+		{name: "bool slice", value: []bool{}, wantType: "bool[]"},
+		{name: "pointer slice", value: []*string{}, wantType: "string[]"},
+		{name: "array", value: [2]float64{}, wantType: "float[]"},
+		{name: "struct", value: schemaTestAddress{}, wantType: "object"},
+		{name: "struct slice", value: []schemaTestAddress{}, wantType: "object[]"},
+		{name: "string map", value: map[string]interface{}{}, wantType: "object"},
+		{name: "time", value: time.Time{}, wantType: "int64"},
+		{name: "time pointer", value: &time.Time{}, wantType: "int64", wantOptional: true},
+		{name: "time slice", value: []time.Time{}, wantType: "int64[]"},
+		{name: "timestamp", value: types.Timestamp{}, wantType: "int64"},
+		{name: "geopoint", value: types.GeoPoint{}, wantType: "geopoint"},
+		{name: "geopoint slice", value: []types.GeoPoint{}, wantType: "geopoint[]"},
+		{name: "registered struct", value: schemaTestMoney{}, wantType: "float"},
+		{name: "registered over kind", value: schemaTestStatus(0), wantType: "string"},
+		{name: "registered slice element", value: []schemaTestMoney{}, wantType: "float[]"},
+		{name: "registered pointer", value: &schemaTestMoney{}, wantType: "float", wantOptional: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			typ, optional, err := golangToTypesenseType(reflect.TypeOf(test.value))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if typ != test.wantType || optional != test.wantOptional {
+				t.Fatalf("got %q , optional %v , want %q , optional %v", typ, optional, test.wantType, test.wantOptional)
+			}
+		})
+	}
+}
+
+func TestGolangToTypesenseTypeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "nested slice", value: [][]string{}},
+		{name: "int keyed map", value: map[int]string{}},
+		{name: "channel", value: make(chan int)},
+		{name: "func", value: func() {}},
+		{name: "complex", value: complex64(0)},
+		{name: "interface", value: new(interface{})},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := golangToTypesenseType(reflect.TypeOf(test.value))
+			if err == nil || !strings.HasPrefix(err.Error(), "Typesense : Unsupported") {
+				t.Fatalf("err = %v", err)
+			}
+		})
+	}
+}
+
+func TestAddVectorOptions(t *testing.T) {
+	SetEmbedModelConfig(EmbedModelConfig{ModelName: "openai/text-embedding-ada-002", APIKey: "sk-test"})
+	t.Cleanup(func() {
+		embedModelConfigsMu.Lock()
+		defer embedModelConfigsMu.Unlock()
+		delete(embedModelConfigs, "openai/text-embedding-ada-002")
+	})
+
+	tests := []struct {
+		name    string
+		tag     reflect.StructTag
+		typ     string
+		want    CollectionField
+		wantErr string
+	}{
+		{name: "not a vector", tag: `json:"embedding"`, typ: "float[]", want: CollectionField{Type: "float[]"}},
+		{name: "num dim", tag: `tsense_num_dim:"384"`, typ: "float[]", want: CollectionField{Type: "float[]", NumDim: 384, Index: true}},
+		{name: "num dim and distance", tag: `tsense_num_dim:"3" tsense_vec_dist:"ip"`, typ: "float[]", want: CollectionField{Type: "float[]", NumDim: 3, VecDist: "ip", Index: true}},
+		{
+			name: "embedding",
+			tag:  `tsense_embed_from:"name,description" tsense_embed_model:"ts/all-MiniLM-L12-v2" tsense_vec_dist:"cosine"`,
+			typ:  "float[]",
+			want: CollectionField{
+				Type:    "float[]",
+				VecDist: "cosine",
+				Index:   true,
+				Embed:   &FieldEmbed{From: []string{"name", "description"}, ModelConfig: EmbedModelConfig{ModelName: "ts/all-MiniLM-L12-v2"}},
+			},
+		},
+		{
+			name: "configured embedding model",
+			tag:  `tsense_embed_from:"name" tsense_embed_model:"openai/text-embedding-ada-002"`,
+			typ:  "float[]",
+			want: CollectionField{
+				Type:  "float[]",
+				Index: true,
+				Embed: &FieldEmbed{From: []string{"name"}, ModelConfig: EmbedModelConfig{ModelName: "openai/text-embedding-ada-002", APIKey: "sk-test"}},
+			},
+		},
+		{name: "distance without a vector", tag: `tsense_vec_dist:"ip"`, typ: "float[]", wantErr: "has a tsense_vec_dist but is not a vector"},
+		{name: "distance on a string", tag: `tsense_vec_dist:"ip"`, typ: "string", wantErr: "is not a vector"},
+		{name: "unknown distance", tag: `tsense_num_dim:"3" tsense_vec_dist:"l2"`, typ: "float[]", wantErr: "invalid tsense_vec_dist value 'l2'"},
+		{name: "num dim not a number", tag: `tsense_num_dim:"abc"`, typ: "float[]", wantErr: "invalid tsense_num_dim value 'abc'"},
+		{name: "num dim zero", tag: `tsense_num_dim:"0"`, typ: "float[]", wantErr: "invalid tsense_num_dim value '0'"},
+		{name: "num dim on a string", tag: `tsense_num_dim:"3"`, typ: "string", wantErr: "must be a float slice , got string"},
+		{name: "embedding without a model", tag: `tsense_embed_from:"name"`, typ: "float[]", wantErr: "has no tsense_embed_model"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			colField := CollectionField{Name: "embedding", Type: test.typ}
+			err := addVectorOptions(&colField, reflect.StructField{Name: "Embedding", Tag: test.tag})
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("err = %v , want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			test.want.Name = "embedding"
+			if !reflect.DeepEqual(colField, test.want) {
+				t.Fatalf("got %+v , want %+v", colField, test.want)
+			}
+		})
+	}
+}
@@ -1,21 +1,52 @@
 package typesense
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/baderkha/typesense/filter"
+	"github.com/baderkha/typesense/pkg/conditional"
+	http2 "github.com/baderkha/typesense/pkg/http"
+	"github.com/baderkha/typesense/types"
+)
+
+const (
+	// SortAsc : ascending sort order
+	SortAsc = "asc"
+	// SortDesc : descending sort order
+	SortDesc = "desc"
+
+	// GeoUnitKm : geo radius in kilometers
+	GeoUnitKm = "km"
+	// GeoUnitMi : geo radius in miles
+	GeoUnitMi = "mi"
+
+	// FacetStrategyExhaustive : counts every facet value (accurate , slower for high cardinality fields)
+	FacetStrategyExhaustive = "exhaustive"
+	// FacetStrategyTopValues : only counts the top values (faster , less accurate)
+	FacetStrategyTopValues = "top_values"
+	// FacetStrategyAutomatic : lets typesense pick the strategy (default)
+	FacetStrategyAutomatic = "automatic"
 )
 
 var (
 	hasSearchCache        bool = true
 	searchCacheTTLSeconds int  = 60
+	// isSearchCacheSet : use_cache is only sent once SetSearchCache was called (otherwise the server default applies)
+	isSearchCacheSet bool
 )
 
-// SetSearchCache : sets search cache when using the search client
+// SetSearchCache : sets search cache when using the search client , until it's called searches that don't set
+// UseCache leave caching to the typesense server (off by default)
 func SetSearchCache(isSearchCache bool) {
 	hasSearchCache = isSearchCache
+	isSearchCacheSet = true
 }
 
-// SetSearchCache : sets search cache when using the search client
+// SetSearchCacheTTL : sets how long search results are cached (in seconds) when using the search client
 func SetSearchCacheTTL(searchCacheTTL int) {
 	searchCacheTTLSeconds = searchCacheTTL
 }
@@ -26,15 +57,64 @@ type SearchParameters struct {
 	QueryBy    string `json:"query_by,omitempty"`
 	FilterBy   string `json:"filter_by,omitempty"`
 	SortBy     string `json:"sort_by,omitempty"`
-	Page       string `json:"page,omitempty"`
-	PerPage    string `json:"per_page,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page,omitempty"`
+
+	VectorQuery         string `json:"vector_query,omitempty"`
+	RerankHybridMatches bool   `json:"rerank_hybrid_matches,omitempty"`
+
+	FacetBy           string `json:"facet_by,omitempty"`
+	MaxFacetValues    int    `json:"max_facet_values,omitempty"`
+	FacetQuery        string `json:"facet_query,omitempty"`
+	FacetStrategy     string `json:"facet_strategy,omitempty"`
+	FacetReturnParent string `json:"facet_return_parent,omitempty"`
+
+	// comma separated per query by field values (a single value applies to all of them) , see the setters
+	QueryByWeights string `json:"query_by_weights,omitempty"`
+	Prefix         string `json:"prefix,omitempty"`
+	Infix          string `json:"infix,omitempty"`
+	NumTypos       string `json:"num_typos,omitempty"`
+
+	// pointers are for params where the zero value means something to typesense , nil uses the typesense default
+	MinLen1Typo         *int   `json:"min_len_1typo,omitempty"`
+	MinLen2Typo         *int   `json:"min_len_2typo,omitempty"`
+	DropTokensThreshold *int   `json:"drop_tokens_threshold,omitempty"`
+	TypoTokensThreshold *int   `json:"typo_tokens_threshold,omitempty"`
+	SplitJoinTokens     string `json:"split_join_tokens,omitempty"`
+
+	IncludeFields       string `json:"include_fields,omitempty"`
+	ExcludeFields       string `json:"exclude_fields,omitempty"`
+	HighlightFields     string `json:"highlight_fields,omitempty"`
+	HighlightFullFields string `json:"highlight_full_fields,omitempty"`
+	HighlightStartTag   string `json:"highlight_start_tag,omitempty"`
+	HighlightEndTag     string `json:"highlight_end_tag,omitempty"`
+	SnippetThreshold    *int   `json:"snippet_threshold,omitempty"`
+
+	PinnedHits           string `json:"pinned_hits,omitempty"`
+	HiddenHits           string `json:"hidden_hits,omitempty"`
+	EnableOverrides      *bool  `json:"enable_overrides,omitempty"`
+	PrioritizeExactMatch *bool  `json:"prioritize_exact_match,omitempty"`
+	ExhaustiveSearch     *bool  `json:"exhaustive_search,omitempty"`
+	SearchCutoffMs       int    `json:"search_cutoff_ms,omitempty"`
+
+	// UseCache / CacheTTL : nil uses the SetSearchCache / SetSearchCacheTTL defaults (nothing is sent if SetSearchCache
+	// was never called)
+	UseCache *bool `json:"use_cache,omitempty"`
+	CacheTTL *int  `json:"cache_ttl,omitempty"`
+
+	LimitHits int  `json:"limit_hits,omitempty"`
+	Offset    *int `json:"offset,omitempty"`
+	Limit     int  `json:"limit,omitempty"`
+
+	// err : first error hit while building the params (ie an invalid filter) , returned by the search
+	err error
 }
 
 // SearchGroupedParameters : Search Parametes with grouping added
 type SearchGroupedParameters struct {
 	SearchParameters
 	GroupBy    string `json:"group_by,omitempty"`
-	GroupLimit string `json:"group_limit,omitempty"`
+	GroupLimit int    `json:"group_limit,omitempty"`
 }
 
 func NewSearchParams() *SearchParameters {
@@ -55,11 +135,11 @@ func (s *SearchParameters) AddQueryBy(fieldQueryBy string) *SearchParameters {
 	return s
 }
 func (s *SearchParameters) AddPage(page int) *SearchParameters {
-	s.Page = fmt.Sprintf("%d", page)
+	s.Page = page
 	return s
 }
 func (s *SearchParameters) AddPerPage(perPage int) *SearchParameters {
-	s.PerPage = fmt.Sprintf("%d", perPage)
+	s.PerPage = perPage
 	return s
 }
 func (s *SearchParameters) AddFilterBy(fieldFilterBy string) *SearchParameters {
@@ -67,27 +147,316 @@ func (s *SearchParameters) AddFilterBy(fieldFilterBy string) *SearchParameters {
 	return s
 }
 
+// AddFilter : adds a filter built with the filter package , joined to the existing filter_by with &&
+//
+// Example :
+//			params := typesense.
+//				NewSearchParams().
+//				AddFilter(filter.And(
+//					filter.Field("brand").In("Nike", "Adidas"),
+//					filter.Field("price").Range(10, 100),
+//				))
+//
+func (s *SearchParameters) AddFilter(expr filter.Expr) *SearchParameters {
+	if expr == nil {
+		return s
+	}
+	filterBy, err := expr.Build()
+	if err != nil {
+		s.setErr(err)
+		return s
+	}
+	if filterBy != "" {
+		s.FilterBy = appendFilter(s.FilterBy, filterBy)
+	}
+	return s
+}
+
+// setErr : keeps the first error , the search returns it without calling typesense
+func (s *SearchParameters) setErr(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
 func (s *SearchParameters) AddSortBy(SortBy string) *SearchParameters {
 	s.SortBy = SortBy
 	return s
 }
 
+// AddSort : sets the sort by from a sort builder (see NewSortBy) , replacing the existing sort by
+func (s *SearchParameters) AddSort(sortBy SortExpr) *SearchParameters {
+	if sortBy == nil {
+		return s
+	}
+	built, err := sortBy.Build()
+	if err != nil {
+		s.setErr(err)
+		return s
+	}
+	s.SortBy = built
+	return s
+}
+
+// AddQueryByFields : query by referenced model fields (see Field) , fails the search if a field is not part of the model
+func (s *SearchParameters) AddQueryByFields(refs ...FieldReference) *SearchParameters {
+	names, err := fieldRefNames(refs, FieldReference.FieldName)
+	if err != nil {
+		s.setErr(err)
+		return s
+	}
+	return s.AddQueryBy(names)
+}
+
+// AddFacetByFields : facet by referenced model fields (see Field) , fails the search if a field has no tsense_facet tag
+func (s *SearchParameters) AddFacetByFields(refs ...FieldReference) *SearchParameters {
+	names, err := fieldRefNames(refs, FieldReference.Facetable)
+	if err != nil {
+		s.setErr(err)
+		return s
+	}
+	return s.AddFacetBy(names)
+}
+
+// AddSortByField : sort by a referenced model field (see Field) , appended to the existing sort by .
+// fails the search if the field has no tsense_sort / tsense_default_sort tag
+//
+// Example :
+//			params.AddSortByField(typesense.Field[Product]("Price"), typesense.SortDesc)
+//
+func (s *SearchParameters) AddSortByField(ref FieldReference, order string) *SearchParameters {
+	if order != SortAsc && order != SortDesc {
+		s.setErr(fmt.Errorf("Typesense : unknown sort order '%s' , use asc or desc", order))
+		return s
+	}
+	name, err := fieldRefNames([]FieldReference{ref}, FieldReference.Sortable)
+	if err != nil {
+		s.setErr(err)
+		return s
+	}
+	sortBy := fmt.Sprintf("%s:%s", name, order)
+	s.SortBy = conditional.Ternary(s.SortBy == "", sortBy, s.SortBy+","+sortBy)
+	return s
+}
+
+// fieldRefNames : resolves the references with one of the FieldReference methods , comma separated
+func fieldRefNames(refs []FieldReference, resolve func(FieldReference) (string, error)) (string, error) {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref == nil {
+			return "", fmt.Errorf("Typesense : field reference cannot be nil")
+		}
+		name, err := resolve(ref)
+		if err != nil {
+			return "", err
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ","), nil
+}
+
+// AddGeoRadiusFilter : only match documents where the geopoint field is within the radius of the center
+//
+// Example :
+//			// location:(48.85, 2.29, 5.1 km)
+//			params.AddGeoRadiusFilter("location", types.GeoPoint{Lat: 48.85, Lng: 2.29}, 5.1, typesense.GeoUnitKm)
+//
+func (s *SearchParameters) AddGeoRadiusFilter(field string, center types.GeoPoint, radius float64, unit string) *SearchParameters {
+	return s.AddFilter(filter.Field(field).WithinRadius(center, radius, unit))
+}
+
+// AddGeoPolygonFilter : only match documents where the geopoint field is inside the polygon
+//
+// Example :
+//			// location:(48.8, 2.3, 48.9, 2.3, 48.9, 2.4)
+//			params.AddGeoPolygonFilter("location", types.GeoPoint{Lat: 48.8, Lng: 2.3}, types.GeoPoint{Lat: 48.9, Lng: 2.3}, types.GeoPoint{Lat: 48.9, Lng: 2.4})
+//
+func (s *SearchParameters) AddGeoPolygonFilter(field string, polygon ...types.GeoPoint) *SearchParameters {
+	return s.AddFilter(filter.Field(field).WithinPolygon(polygon...))
+}
+
+// AddGeoDistanceSort : sort by the distance between the geopoint field and a point , appended to the existing sort by
+//
+// the distance is returned per hit in Hit.GeoDistanceMeters
+//
+// Example :
+//			// location(48.85, 2.29):asc
+//			params.AddGeoDistanceSort("location", types.GeoPoint{Lat: 48.85, Lng: 2.29}, typesense.SortAsc)
+//
+func (s *SearchParameters) AddGeoDistanceSort(field string, from types.GeoPoint, order string) *SearchParameters {
+	sortBy := fmt.Sprintf("%s(%s, %s):%s", field, formatFloat(from.Lat), formatFloat(from.Lng), order)
+	s.SortBy = conditional.Ternary(s.SortBy == "", sortBy, s.SortBy+","+sortBy)
+	return s
+}
+
+// AddFacetBy : comma separated list of fields to facet by , read the counts with SearchResult.FacetValues
+func (s *SearchParameters) AddFacetBy(fieldFacetBy string) *SearchParameters {
+	s.FacetBy = fieldFacetBy
+	return s
+}
+
+// AddMaxFacetValues : max number of values returned per faceted field (typesense default 10)
+func (s *SearchParameters) AddMaxFacetValues(maxFacetValues int) *SearchParameters {
+	s.MaxFacetValues = maxFacetValues
+	return s
+}
+
+// AddFacetQuery : only count the facet values matching the query (ie for facet search boxes)
+//
+// Example :
+//			// brand:nik
+//			params.AddFacetBy("brand").AddFacetQuery("brand", "nik")
+//
+func (s *SearchParameters) AddFacetQuery(field string, query string) *SearchParameters {
+	s.FacetQuery = fmt.Sprintf("%s:%s", field, query)
+	return s
+}
+
+// AddFacetStrategy : how facet values are counted , see FacetStrategyExhaustive , FacetStrategyTopValues , FacetStrategyAutomatic
+func (s *SearchParameters) AddFacetStrategy(facetStrategy string) *SearchParameters {
+	s.FacetStrategy = facetStrategy
+	return s
+}
+
+// AddFacetReturnParent : comma separated list of nested fields whose parent object is returned with the facet values
+func (s *SearchParameters) AddFacetReturnParent(fieldFacetReturnParent string) *SearchParameters {
+	s.FacetReturnParent = fieldFacetReturnParent
+	return s
+}
+
+// VectorQuery : nearest neighbour search on a vector field , either by a query vector or by the vector of a document
+//
+// see https://typesense.org/docs/latest/api/vector-search.html
+type VectorQuery struct {
+	// Field : the float[] field to search
+	Field string
+	// Vector : the query vector (leave empty when using ID , or when the field embeds the query text itself)
+	Vector []float32
+	// ID : find documents similar to this document
+	ID string
+	// K : number of nearest neighbours to return
+	K int
+	// DistanceThreshold : only return neighbours closer than this distance
+	DistanceThreshold float64
+	// Alpha : weight of the vector search in a hybrid search (0 - 1) , the text match gets 1 - alpha (typesense default 0.3)
+	Alpha float64
+}
+
+// String : the vector_query param
+//
+// Example :
+//			// embedding:([0.96826, 0.94, 0.39557], k:100, distance_threshold:0.3)
+//			(&typesense.VectorQuery{Field: "embedding", Vector: []float32{0.96826, 0.94, 0.39557}, K: 100, DistanceThreshold: 0.3}).String()
+func (v *VectorQuery) String() string {
+	var vector []string
+	for _, value := range v.Vector {
+		vector = append(vector, strconv.FormatFloat(float64(value), 'f', -1, 32))
+	}
+	options := []string{fmt.Sprintf("[%s]", strings.Join(vector, ", "))}
+	if v.ID != "" {
+		options = append(options, fmt.Sprintf("id:%s", v.ID))
+	}
+	if v.K > 0 {
+		options = append(options, fmt.Sprintf("k:%d", v.K))
+	}
+	if v.DistanceThreshold > 0 {
+		options = append(options, fmt.Sprintf("distance_threshold:%s", formatFloat(v.DistanceThreshold)))
+	}
+	if v.Alpha > 0 {
+		options = append(options, fmt.Sprintf("alpha:%s", formatFloat(v.Alpha)))
+	}
+	return fmt.Sprintf("%s:(%s)", v.Field, strings.Join(options, ", "))
+}
+
+// AddVectorQuery : nearest neighbour search on a vector field , the distance is returned per hit in Hit.VectorDistance
+//
+// keep in mind typesense limits the query string of a GET search to 4000 characters , send large vectors
+// through a MultiSearch instead (the params are sent in the request body)
+//
+// Example :
+//			params := typesense.
+//				NewSearchParams().
+//				AddVectorQuery(&typesense.VectorQuery{Field: "embedding", Vector: queryVector, K: 10})
+//
+func (s *SearchParameters) AddVectorQuery(vectorQuery *VectorQuery) *SearchParameters {
+	s.VectorQuery = vectorQuery.String()
+	return s
+}
+
+// AddHybridQuery : keyword + vector search in one query , typesense merges both rankings with rank fusion
+// weighted by VectorQuery.Alpha . each hit gets Hit.TextMatch , Hit.VectorDistance and Hit.HybridSearchInfo
+//
+// when the vector field embeds text (tsense_embed_from) , add it to queryBy and leave the vector empty
+//
+// Example :
+//			params := typesense.
+//				NewSearchParams().
+//				AddHybridQuery("running shoes", "name,description,embedding", &typesense.VectorQuery{
+//					Field: "embedding",
+//					Alpha: 0.8, // favour the semantic match
+//				}).
+//				AddRerankHybridMatches(true)
+//
+func (s *SearchParameters) AddHybridQuery(q string, queryBy string, vectorQuery *VectorQuery) *SearchParameters {
+	return s.
+		AddSearchTerm(q).
+		AddQueryBy(queryBy).
+		AddVectorQuery(vectorQuery)
+}
+
+// AddRerankHybridMatches : compute both the text match and the vector distance for every hybrid search hit ,
+// even the ones only found by one of the searches
+func (s *SearchParameters) AddRerankHybridMatches(rerank bool) *SearchParameters {
+	s.RerankHybridMatches = rerank
+	return s
+}
+
+// appendFilter : joins a filter to an existing filter_by expression with && (either side is wrapped if it has a top level ||)
+func appendFilter(filterBy string, newFilter string) string {
+	if filterBy == "" {
+		return newFilter
+	}
+	if filter.HasTopLevelOr(filterBy) {
+		filterBy = fmt.Sprintf("(%s)", filterBy)
+	}
+	if filter.HasTopLevelOr(newFilter) {
+		newFilter = fmt.Sprintf("(%s)", newFilter)
+	}
+	return fmt.Sprintf("%s && %s", filterBy, newFilter)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
 func (s *SearchGroupedParameters) AddGroupBy(GroupBy string) *SearchGroupedParameters {
 	s.GroupBy = GroupBy
 	return s
 }
 
 func (s *SearchGroupedParameters) AddGroupLimit(GroupLimit int) *SearchGroupedParameters {
-	s.GroupLimit = fmt.Sprintf("%d", GroupLimit)
+	s.GroupLimit = GroupLimit
 	return s
 }
 
 // ISearchClient : search client interface
 type ISearchClient[T any] interface {
-	// Search : search without grouping and allows for pagination
+	// Search : search without grouping and allows for pagination .
+	// failed requests return a *SearchError (see IsNotFound , IsInvalidQuery , IsServerError)
 	Search(s *SearchParameters) (SearchResult[T], error)
 	// SearchGrouped : search with grouping by field and allows for pagniantion
 	SearchGrouped(s *SearchGroupedParameters) (SearchResultGrouped[T], error)
+	// Iterate : walks the hits of every page of the search one at a time (no more hand rolled page loops)
+	//
+	// Example :
+	//			it := searchClient.Iterate(typesense.NewSearchParams().AddPerPage(100))
+	//			for it.Next() {
+	//				fmt.Println(it.Hit().Document)
+	//			}
+	//			if err := it.Err(); err != nil {
+	//				log.Fatal(err)
+	//			}
+	Iterate(s *SearchParameters) *SearchIterator[T]
 	// WithCollectionName : Override the collection name for local operations and not globally
 	WithCollectionName(colName string) ISearchClient[T]
 	// WithoutDocAutoAlias : if you used the migration tool , it probably auto aliased your collection . if you're doing your own migration
@@ -109,21 +478,57 @@ type SearchClient[T any] struct {
 	*baseClient[T]
 }
 
+// toQueryParams : flattens the json representation of the search parameters to query params
+func toQueryParams(queryParams any) (map[string]string, error) {
+	b, err := json.Marshal(queryParams)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	err = decoder.Decode(&values)
+	if err != nil {
+		return nil, err
+	}
+	params := make(map[string]string, len(values))
+	for key, value := range values {
+		if value != nil {
+			params[key] = fmt.Sprint(value)
+		}
+	}
+	return params, nil
+}
+
 func (s *SearchClient[T]) searchRestAny(queryParams any, castValue interface{}) error {
-	b, _ := json.Marshal(queryParams)
-	var params map[string]string
-	_ = json.Unmarshal(b, &params)
-	s.Req().
+	params, err := toQueryParams(queryParams)
+	if err != nil {
+		return err
+	}
+	addSearchCacheDefaults(params)
+	colName := s.resolveColName()
+	res, err := s.Req().
 		SetQueryParams(params).
 		SetResult(castValue).
-		Get(fmt.Sprintf("/collections/%s/documents/search", s.resolveColName()))
+		Get(fmt.Sprintf("/collections/%s/documents/search", colName))
+	if err != nil {
+		searchErr := &SearchError{Collection: colName, Err: err}
+		if res != nil && res.RawResponse != nil {
+			searchErr.StatusCode = res.StatusCode()
+		}
+		return searchErr
+	} else if !http2.StatusIsSuccess(res.StatusCode()) {
+		return newSearchError(colName, res.StatusCode(), res.Body())
+	}
 	return nil
-
 }
 
 // Search : search without grouping and allows for pagination
 func (s *SearchClient[T]) Search(search *SearchParameters) (SearchResult[T], error) {
 	var res SearchResult[T]
+	if search.err != nil {
+		return res, search.err
+	}
 	err := s.searchRestAny(search, &res)
 	return res, err
 }
@@ -131,6 +536,9 @@ func (s *SearchClient[T]) Search(search *SearchParameters) (SearchResult[T], err
 // SearchGrouped : search with grouping by field and allows for pagniantion
 func (s *SearchClient[T]) SearchGrouped(search *SearchGroupedParameters) (SearchResultGrouped[T], error) {
 	var res SearchResultGrouped[T]
+	if search.err != nil {
+		return res, search.err
+	}
 	err := s.searchRestAny(search, &res)
 	return res, err
 }
@@ -0,0 +1,78 @@
+package typesense
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// SearchError : a failed search , tells an invalid query apart from a missing collection or a server outage
+//
+// Example :
+//			res, err := searchClient.Search(params)
+//			var searchErr *typesense.SearchError
+//			if errors.As(err, &searchErr) && searchErr.IsNotFound() {
+//				// the collection (or alias) does not exist
+//			}
+//
+type SearchError struct {
+	// StatusCode : the http status typesense responded with , 0 if typesense could not be reached
+	StatusCode int
+	// Message : the error message typesense responded with
+	Message string
+	// Collection : the collection (or alias) searched , empty for a failed multi search request
+	Collection string
+	// Err : the underlying error when the request itself failed (connection , decoding ..etc)
+	Err error
+}
+
+// newSearchError : reads the message out of a typesense error response ({"message": "..."})
+func newSearchError(colName string, statusCode int, responseBody []byte) *SearchError {
+	var body struct {
+		Message string `json:"message"`
+	}
+	message := strings.TrimSpace(string(responseBody))
+	if json.Unmarshal(responseBody, &body) == nil && body.Message != "" {
+		message = body.Message
+	}
+	return &SearchError{
+		StatusCode: statusCode,
+		Message:    message,
+		Collection: colName,
+	}
+}
+
+func (e *SearchError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("Typesense : search on %s failed : %s", e.collectionName(), e.Err.Error())
+	}
+	return fmt.Sprintf("%s : %d  : %s (searching %s)", typesenseErrPrefix, e.StatusCode, e.Message, e.collectionName())
+}
+
+func (e *SearchError) collectionName() string {
+	if e.Collection == "" {
+		return "multi search"
+	}
+	return e.Collection
+}
+
+// Unwrap : the underlying request error if any
+func (e *SearchError) Unwrap() error {
+	return e.Err
+}
+
+// IsNotFound : the collection (or alias) does not exist
+func (e *SearchError) IsNotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsInvalidQuery : typesense rejected the search params (ie a bad filter_by or an unknown query_by field)
+func (e *SearchError) IsInvalidQuery() bool {
+	return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+}
+
+// IsServerError : typesense failed or could not be reached , the search may succeed if retried
+func (e *SearchError) IsServerError() bool {
+	return e.StatusCode >= http.StatusInternalServerError || (e.StatusCode == 0 && e.Err != nil)
+}
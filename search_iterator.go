@@ -0,0 +1,105 @@
+package typesense
+
+const (
+	// defaultIteratorPerPage : page size of the search iterator when the params have no per page (typesense max 250)
+	defaultIteratorPerPage = 250
+)
+
+// SearchIterator : walks the hits of every page of a search one at a time , created with ISearchClient.Iterate
+//
+// Example :
+//			it := client.Search().Iterate(typesense.NewSearchParams().AddFilterBy("country:=FR"))
+//			for it.Next() {
+//				hit := it.Hit()
+//				fmt.Println(hit.Document)
+//			}
+//			if err := it.Err(); err != nil {
+//				log.Fatal(err)
+//			}
+//
+type SearchIterator[T any] struct {
+	client ISearchClient[T]
+	params SearchParameters
+	hits   []Hit[T]
+	pos    int
+	seen   int
+	found  int
+	hit    Hit[T]
+	err    error
+	done   bool
+}
+
+// Iterate : walks every page of the search , the iterator stops at the number of found documents (or limit hits)
+func (s *SearchClient[T]) Iterate(search *SearchParameters) *SearchIterator[T] {
+	return newSearchIterator[T](s, search)
+}
+
+func newSearchIterator[T any](client ISearchClient[T], search *SearchParameters) *SearchIterator[T] {
+	params := *search
+	// pages are managed by the iterator
+	params.Page = 0
+	params.Offset = nil
+	params.Limit = 0
+	if params.PerPage <= 0 {
+		params.PerPage = defaultIteratorPerPage
+	}
+	return &SearchIterator[T]{
+		client: client,
+		params: params,
+	}
+}
+
+// Next : moves to the next hit , fetching the next page when needed . false once every hit was read or on error
+func (it *SearchIterator[T]) Next() bool {
+	if it.err != nil || it.done {
+		return false
+	}
+	if it.params.LimitHits > 0 && it.seen >= it.params.LimitHits {
+		it.done = true
+		return false
+	}
+	if it.pos >= len(it.hits) && !it.fetchNextPage() {
+		return false
+	}
+	it.hit = it.hits[it.pos]
+	it.pos++
+	it.seen++
+	return true
+}
+
+// Hit : the current hit
+func (it *SearchIterator[T]) Hit() Hit[T] {
+	return it.hit
+}
+
+// Err : the error that stopped the iterator if any
+func (it *SearchIterator[T]) Err() error {
+	return it.err
+}
+
+// Found : the number of documents found by the search (0 until the first page is fetched)
+func (it *SearchIterator[T]) Found() int {
+	return it.found
+}
+
+func (it *SearchIterator[T]) fetchNextPage() bool {
+	// the last page was short or everything was read already
+	if it.params.Page > 0 && (len(it.hits) < it.params.PerPage || it.seen >= it.found) {
+		it.done = true
+		return false
+	}
+	it.params.Page++
+	res, err := it.client.Search(&it.params)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.found = res.Found
+	it.hits = res.Hits
+	it.pos = 0
+	if len(it.hits) == 0 {
+		it.done = true
+		return false
+	}
+	return true
+}
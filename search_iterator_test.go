@@ -0,0 +1,126 @@
+package typesense
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// paginatedSearchHandler : serves found documents (id 1..found) page by page , honouring per_page and limit_hits
+func paginatedSearchHandler(t *testing.T, found int, pages *[]int) func(w http.ResponseWriter, r *http.Request) {
+	var mu sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		page, _ := strconv.Atoi(query.Get("page"))
+		perPage, _ := strconv.Atoi(query.Get("per_page"))
+		if page < 1 || perPage < 1 || query.Get("offset") != "" || query.Get("limit") != "" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		*pages = append(*pages, page)
+		mu.Unlock()
+		last := found
+		if limitHits, _ := strconv.Atoi(query.Get("limit_hits")); limitHits > 0 && limitHits < last {
+			last = limitHits
+		}
+		hits := []Hit[searchTestProduct]{}
+		for id := (page-1)*perPage + 1; id <= page*perPage && id <= last; id++ {
+			hits = append(hits, Hit[searchTestProduct]{Document: searchTestProduct{ID: fmt.Sprint(id)}, Highlights: []Highlights{}})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"found":  found,
+			"hits":   hits,
+			"page":   page,
+			"out_of": found,
+		})
+	}
+}
+
+func TestSearchIterator(t *testing.T) {
+	tests := []struct {
+		name      string
+		found     int
+		params    *SearchParameters
+		wantIDs   int
+		wantPages []int
+	}{
+		{name: "short last page", found: 7, params: NewSearchParams().AddPerPage(3), wantIDs: 7, wantPages: []int{1, 2, 3}},
+		// the last page is full , found stops the iterator without asking for an empty page
+		{name: "exactly full last page", found: 6, params: NewSearchParams().AddPerPage(3), wantIDs: 6, wantPages: []int{1, 2}},
+		{name: "single page", found: 2, params: NewSearchParams().AddPerPage(3), wantIDs: 2, wantPages: []int{1}},
+		{name: "nothing found", found: 0, params: NewSearchParams().AddPerPage(3), wantIDs: 0, wantPages: []int{1}},
+		{name: "limit hits below found", found: 10, params: NewSearchParams().AddPerPage(3).AddLimitHits(4), wantIDs: 4, wantPages: []int{1, 2}},
+		{name: "limit hits on a page boundary", found: 10, params: NewSearchParams().AddPerPage(3).AddLimitHits(6), wantIDs: 6, wantPages: []int{1, 2}},
+		{name: "limit hits above found", found: 5, params: NewSearchParams().AddPerPage(3).AddLimitHits(50), wantIDs: 5, wantPages: []int{1, 2}},
+		{name: "page and offset are ignored", found: 4, params: NewSearchParams().AddPerPage(3).AddPage(2).AddOffset(1).AddLimit(1), wantIDs: 4, wantPages: []int{1, 2}},
+		{name: "default per page", found: 251, params: NewSearchParams(), wantIDs: 251, wantPages: []int{1, 2}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var pages []int
+			server := newSearchTestServer(t, paginatedSearchHandler(t, test.found, &pages))
+			client := NewSearchClient[searchTestProduct]("key", server.URL, false)
+
+			it := client.Iterate(test.params)
+			var ids []string
+			for it.Next() {
+				ids = append(ids, it.Hit().Document.ID)
+			}
+			if it.Err() != nil {
+				t.Fatal(it.Err())
+			}
+			if len(ids) != test.wantIDs {
+				t.Fatalf("got %d hits , want %d", len(ids), test.wantIDs)
+			}
+			for i, id := range ids {
+				if id != fmt.Sprint(i+1) {
+					t.Fatalf("hit %d = %s", i, id)
+				}
+			}
+			if !reflect.DeepEqual(pages, test.wantPages) {
+				t.Fatalf("pages = %v , want %v", pages, test.wantPages)
+			}
+			if it.Found() != test.found {
+				t.Fatalf("Found() = %d", it.Found())
+			}
+			// the iterator stays done
+			if it.Next() || len(pages) != len(test.wantPages) {
+				t.Fatalf("Next() after the end fetched pages %v", pages)
+			}
+		})
+	}
+}
+
+func TestSearchIteratorError(t *testing.T) {
+	var pages []int
+	handler := paginatedSearchHandler(t, 10, &pages)
+	server := newSearchTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message": "Not Ready or Lagging"}`))
+			return
+		}
+		handler(w, r)
+	})
+	client := NewSearchClient[searchTestProduct]("key", server.URL, false)
+
+	it := client.Iterate(NewSearchParams().AddPerPage(3))
+	seen := 0
+	for it.Next() {
+		seen++
+	}
+	var searchErr *SearchError
+	if seen != 3 || !errors.As(it.Err(), &searchErr) || !searchErr.IsServerError() {
+		t.Fatalf("seen = %d , err = %v", seen, it.Err())
+	}
+	if it.Next() {
+		t.Fatal("Next() after an error")
+	}
+}
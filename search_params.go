@@ -0,0 +1,209 @@
+package typesense
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// InfixOff : no infix search for the field (default)
+	InfixOff = "off"
+	// InfixAlways : infix search along with the regular search
+	InfixAlways = "always"
+	// InfixFallback : infix search only when the regular search has no results
+	InfixFallback = "fallback"
+
+	// SplitJoinTokensOff : never split / join the query tokens
+	SplitJoinTokensOff = "off"
+	// SplitJoinTokensFallback : split / join the query tokens only when the query has no results (default)
+	SplitJoinTokensFallback = "fallback"
+	// SplitJoinTokensAlways : always split / join the query tokens
+	SplitJoinTokensAlways = "always"
+)
+
+// AddQueryByWeights : the weight of each query by field (same order) , higher weighted fields rank first
+func (s *SearchParameters) AddQueryByWeights(weights ...int) *SearchParameters {
+	s.QueryByWeights = joinInts(weights)
+	return s
+}
+
+// AddPrefix : treat the last word of the query as a prefix , one value for all query by fields or one per field
+func (s *SearchParameters) AddPrefix(prefix ...bool) *SearchParameters {
+	values := make([]string, 0, len(prefix))
+	for _, p := range prefix {
+		values = append(values, strconv.FormatBool(p))
+	}
+	s.Prefix = strings.Join(values, ",")
+	return s
+}
+
+// AddInfix : infix search (InfixOff , InfixAlways , InfixFallback) , one value for all query by fields or one per field .
+// the fields need to be created with infix enabled
+func (s *SearchParameters) AddInfix(infix ...string) *SearchParameters {
+	s.Infix = strings.Join(infix, ",")
+	return s
+}
+
+// AddNumTypos : max number of typos (0 - 2) , one value for all query by fields or one per field
+func (s *SearchParameters) AddNumTypos(numTypos ...int) *SearchParameters {
+	s.NumTypos = joinInts(numTypos)
+	return s
+}
+
+// AddMinLen1Typo : min length of a word for 1 typo to be tolerated
+func (s *SearchParameters) AddMinLen1Typo(minLen int) *SearchParameters {
+	s.MinLen1Typo = &minLen
+	return s
+}
+
+// AddMinLen2Typo : min length of a word for 2 typos to be tolerated
+func (s *SearchParameters) AddMinLen2Typo(minLen int) *SearchParameters {
+	s.MinLen2Typo = &minLen
+	return s
+}
+
+// AddDropTokensThreshold : words of the query are dropped until there are at least this many results (0 to never drop)
+func (s *SearchParameters) AddDropTokensThreshold(threshold int) *SearchParameters {
+	s.DropTokensThreshold = &threshold
+	return s
+}
+
+// AddTypoTokensThreshold : typos are tolerated until there are at least this many results (0 to never)
+func (s *SearchParameters) AddTypoTokensThreshold(threshold int) *SearchParameters {
+	s.TypoTokensThreshold = &threshold
+	return s
+}
+
+// AddSplitJoinTokens : splitting / joining of the query words (SplitJoinTokensOff , SplitJoinTokensFallback , SplitJoinTokensAlways)
+func (s *SearchParameters) AddSplitJoinTokens(splitJoinTokens string) *SearchParameters {
+	s.SplitJoinTokens = splitJoinTokens
+	return s
+}
+
+// AddIncludeFields : only return these fields in the documents
+func (s *SearchParameters) AddIncludeFields(fields ...string) *SearchParameters {
+	s.IncludeFields = strings.Join(fields, ",")
+	return s
+}
+
+// AddExcludeFields : do not return these fields in the documents
+func (s *SearchParameters) AddExcludeFields(fields ...string) *SearchParameters {
+	s.ExcludeFields = strings.Join(fields, ",")
+	return s
+}
+
+// AddHighlightFields : the fields to highlight (defaults to the query by fields)
+func (s *SearchParameters) AddHighlightFields(fields ...string) *SearchParameters {
+	s.HighlightFields = strings.Join(fields, ",")
+	return s
+}
+
+// AddHighlightFullFields : the fields to highlight in full instead of a snippet
+func (s *SearchParameters) AddHighlightFullFields(fields ...string) *SearchParameters {
+	s.HighlightFullFields = strings.Join(fields, ",")
+	return s
+}
+
+// AddHighlightTags : the tags wrapped around highlighted words (typesense default <mark></mark>)
+func (s *SearchParameters) AddHighlightTags(startTag string, endTag string) *SearchParameters {
+	s.HighlightStartTag = startTag
+	s.HighlightEndTag = endTag
+	return s
+}
+
+// AddSnippetThreshold : fields shorter than this many words are highlighted in full
+func (s *SearchParameters) AddSnippetThreshold(threshold int) *SearchParameters {
+	s.SnippetThreshold = &threshold
+	return s
+}
+
+// AddPinnedHit : pins the document at the position (starting at 1) , call it once per pinned document
+//
+// Example :
+//			// 123:1,456:2
+//			params.AddPinnedHit("123", 1).AddPinnedHit("456", 2)
+//
+func (s *SearchParameters) AddPinnedHit(id string, position int) *SearchParameters {
+	pinnedHit := fmt.Sprintf("%s:%d", id, position)
+	s.PinnedHits = strings.Trim(s.PinnedHits+","+pinnedHit, ",")
+	return s
+}
+
+// AddHiddenHits : these documents are never returned
+func (s *SearchParameters) AddHiddenHits(ids ...string) *SearchParameters {
+	s.HiddenHits = strings.Join(ids, ",")
+	return s
+}
+
+// AddEnableOverrides : apply the curation rules (overrides) of the collection
+func (s *SearchParameters) AddEnableOverrides(enableOverrides bool) *SearchParameters {
+	s.EnableOverrides = &enableOverrides
+	return s
+}
+
+// AddPrioritizeExactMatch : rank exact matches of the query before the rest
+func (s *SearchParameters) AddPrioritizeExactMatch(prioritizeExactMatch bool) *SearchParameters {
+	s.PrioritizeExactMatch = &prioritizeExactMatch
+	return s
+}
+
+// AddExhaustiveSearch : consider every prefix / typo variation instead of stopping early (slower)
+func (s *SearchParameters) AddExhaustiveSearch(exhaustiveSearch bool) *SearchParameters {
+	s.ExhaustiveSearch = &exhaustiveSearch
+	return s
+}
+
+// AddSearchCutoffMs : return the results found so far once the search takes longer than this
+func (s *SearchParameters) AddSearchCutoffMs(cutoffMs int) *SearchParameters {
+	s.SearchCutoffMs = cutoffMs
+	return s
+}
+
+// AddUseCache : cache the results of this search on the typesense server (overrides SetSearchCache)
+func (s *SearchParameters) AddUseCache(useCache bool) *SearchParameters {
+	s.UseCache = &useCache
+	return s
+}
+
+// AddCacheTTL : how long the results of this search are cached in seconds (overrides SetSearchCacheTTL)
+func (s *SearchParameters) AddCacheTTL(cacheTTLSeconds int) *SearchParameters {
+	s.CacheTTL = &cacheTTLSeconds
+	return s
+}
+
+// AddLimitHits : max number of hits that can be fetched across all the pages
+func (s *SearchParameters) AddLimitHits(limitHits int) *SearchParameters {
+	s.LimitHits = limitHits
+	return s
+}
+
+// AddOffset : the hit to start from (instead of page)
+func (s *SearchParameters) AddOffset(offset int) *SearchParameters {
+	s.Offset = &offset
+	return s
+}
+
+// AddLimit : the number of hits to return from the offset (instead of per page)
+func (s *SearchParameters) AddLimit(limit int) *SearchParameters {
+	s.Limit = limit
+	return s
+}
+
+// addSearchCacheDefaults : the SetSearchCache / SetSearchCacheTTL defaults for searches that don't set them
+func addSearchCacheDefaults(params map[string]string) {
+	if _, ok := params["use_cache"]; !ok && isSearchCacheSet {
+		params["use_cache"] = strconv.FormatBool(hasSearchCache)
+	}
+	if _, ok := params["cache_ttl"]; !ok && params["use_cache"] == "true" {
+		params["cache_ttl"] = strconv.Itoa(searchCacheTTLSeconds)
+	}
+}
+
+func joinInts(values []int) string {
+	formatted := make([]string, 0, len(values))
+	for _, value := range values {
+		formatted = append(formatted, strconv.Itoa(value))
+	}
+	return strings.Join(formatted, ",")
+}
@@ -0,0 +1,44 @@
+package typesense
+
+import "testing"
+
+func TestAddSearchCacheDefaults(t *testing.T) {
+	defer func() {
+		hasSearchCache, searchCacheTTLSeconds, isSearchCacheSet = true, 60, false
+	}()
+
+	tests := []struct {
+		name      string
+		setCache  *bool
+		params    *SearchParameters
+		wantCache string
+		wantTTL   string
+	}{
+		{name: "not set", params: NewSearchParams()},
+		{name: "per search", params: NewSearchParams().AddUseCache(true), wantCache: "true", wantTTL: "60"},
+		{name: "per search ttl", params: NewSearchParams().AddUseCache(true).AddCacheTTL(5), wantCache: "true", wantTTL: "5"},
+		{name: "global on", setCache: boolPtr(true), params: NewSearchParams(), wantCache: "true", wantTTL: "60"},
+		{name: "global off", setCache: boolPtr(false), params: NewSearchParams(), wantCache: "false"},
+		{name: "per search overrides global", setCache: boolPtr(true), params: NewSearchParams().AddUseCache(false), wantCache: "false"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hasSearchCache, searchCacheTTLSeconds, isSearchCacheSet = true, 60, false
+			if test.setCache != nil {
+				SetSearchCache(*test.setCache)
+			}
+			params, err := toQueryParams(test.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			addSearchCacheDefaults(params)
+			if params["use_cache"] != test.wantCache || params["cache_ttl"] != test.wantTTL {
+				t.Fatalf("use_cache = %q , cache_ttl = %q , want %q , %q", params["use_cache"], params["cache_ttl"], test.wantCache, test.wantTTL)
+			}
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
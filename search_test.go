@@ -0,0 +1,339 @@
+package typesense
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type searchTestProduct struct {
+	ID    string   `json:"id"`
+	Name  string   `json:"name"`
+	Brand string   `json:"brand"`
+	Tags  []string `json:"tags"`
+}
+
+// searchTestResponse : a search response as typesense 0.25 sends it
+const searchTestResponse = `{
+  "facet_counts": [
+    {
+      "counts": [{"count": 1, "highlighted": "Nike", "value": "Nike"}],
+      "field_name": "brand",
+      "sampled": false,
+      "stats": {"total_values": 1}
+    }
+  ],
+  "found": 2,
+  "hits": [
+    {
+      "document": {"brand": "Nike", "id": "1", "name": "Nike running shoe", "tags": ["running", "red"]},
+      "highlight": {
+        "name": {"matched_tokens": ["shoe"], "snippet": "Nike running <mark>shoe</mark>"},
+        "tags": [{"matched_tokens": ["running"], "snippet": "<mark>running</mark>"}, {"matched_tokens": [], "snippet": "red"}]
+      },
+      "highlights": [
+        {"field": "name", "matched_tokens": ["shoe"], "snippet": "Nike running <mark>shoe</mark>"},
+        {"field": "tags", "indices": [0], "matched_tokens": [["running"]], "snippets": ["<mark>running</mark>"]}
+      ],
+      "text_match": 578730123365187705,
+      "text_match_info": {
+        "best_field_score": "1108091339008",
+        "best_field_weight": 15,
+        "fields_matched": 1,
+        "num_tokens_dropped": 0,
+        "score": "578730123365187705",
+        "tokens_matched": 1,
+        "typo_prefix_score": 0
+      }
+    },
+    {
+      "document": {"brand": "Adidas", "id": "2", "name": "Adidas shoe", "tags": []},
+      "highlight": {},
+      "highlights": [],
+      "text_match": 578730089005449337
+    }
+  ],
+  "out_of": 3,
+  "page": 1,
+  "request_params": {"collection_name": "products", "first_q": "shoe", "per_page": 10, "q": "shoe"},
+  "search_cutoff": false,
+  "search_time_ms": 1
+}`
+
+func newSearchTestServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if strings.HasPrefix(r.URL.Path, "/aliases/") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSearch(t *testing.T) {
+	var query string
+	server := newSearchTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/collections/search_test_product/documents/search" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(searchTestResponse))
+	})
+	client := NewSearchClient[searchTestProduct]("key", server.URL, false)
+
+	res, err := client.Search(NewSearchParams().AddSearchTerm("shoe").AddQueryBy("name,tags").AddFacetBy("brand"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(query, "q=shoe") || !strings.Contains(query, "query_by=name%2Ctags") || !strings.Contains(query, "facet_by=brand") {
+		t.Fatalf("query = %s", query)
+	}
+	if res.Found != 2 || res.OutOf != 3 || res.Page != 1 || res.RequestParams.CollectionName != "products" || len(res.Hits) != 2 {
+		t.Fatalf("result = %+v", res)
+	}
+
+	hit := res.Hits[0]
+	wantDoc := searchTestProduct{ID: "1", Name: "Nike running shoe", Brand: "Nike", Tags: []string{"running", "red"}}
+	if !reflect.DeepEqual(hit.Document, wantDoc) {
+		t.Fatalf("document = %+v", hit.Document)
+	}
+	if hit.TextMatch != 578730123365187705 || hit.TextMatchInfo == nil || hit.TextMatchInfo.BestFieldWeight != 15 {
+		t.Fatalf("text match = %d , %+v", hit.TextMatch, hit.TextMatchInfo)
+	}
+	wantHighlights := []Highlights{
+		{Field: "name", MatchedTokens: []string{"shoe"}, Snippet: "Nike running <mark>shoe</mark>"},
+		{
+			Field:              "tags",
+			MatchedTokens:      []string{"running"},
+			Snippets:           []string{"<mark>running</mark>"},
+			Indices:            []int{0},
+			ArrayMatchedTokens: [][]string{{"running"}},
+		},
+	}
+	if !reflect.DeepEqual(hit.Highlights, wantHighlights) {
+		t.Fatalf("highlights = %+v", hit.Highlights)
+	}
+	if _, ok := hit.Highlight["name"]; !ok {
+		t.Fatalf("highlight = %+v", hit.Highlight)
+	}
+	if len(res.Hits[1].Highlights) != 0 {
+		t.Fatalf("highlights = %+v", res.Hits[1].Highlights)
+	}
+	if brands := res.FacetValues("brand"); len(brands) != 1 || brands[0].Value != "Nike" || brands[0].Count != 1 {
+		t.Fatalf("facet values = %+v", brands)
+	}
+	if docs := res.GetDocuments(); len(docs) != 2 || docs[1].ID != "2" {
+		t.Fatalf("documents = %+v", docs)
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		wantMessage   string
+		wantNotFound  bool
+		wantInvalid   bool
+		wantServerErr bool
+	}{
+		{
+			name:         "missing collection",
+			status:       http.StatusNotFound,
+			body:         `{"message": "Not found."}`,
+			wantMessage:  "Not found.",
+			wantNotFound: true,
+		},
+		{
+			name:        "bad query by",
+			status:      http.StatusBadRequest,
+			body:        `{"message": "Could not find a field named ` + "`nme`" + ` in the schema."}`,
+			wantMessage: "Could not find a field named `nme` in the schema.",
+			wantInvalid: true,
+		},
+		{
+			name:          "server error without json",
+			status:        http.StatusServiceUnavailable,
+			body:          "Not Ready or Lagging",
+			wantMessage:   "Not Ready or Lagging",
+			wantServerErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newSearchTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.body))
+			})
+			client := NewSearchClient[searchTestProduct]("key", server.URL, false)
+
+			_, err := client.Search(NewSearchParams().AddSearchTerm("shoe").AddQueryBy("nme"))
+			var searchErr *SearchError
+			if !errors.As(err, &searchErr) {
+				t.Fatalf("err = %v , want a *SearchError", err)
+			}
+			if searchErr.StatusCode != test.status || searchErr.Message != test.wantMessage || searchErr.Collection != "search_test_product" {
+				t.Fatalf("search error = %+v", searchErr)
+			}
+			if searchErr.IsNotFound() != test.wantNotFound ||
+				searchErr.IsInvalidQuery() != test.wantInvalid ||
+				searchErr.IsServerError() != test.wantServerErr {
+				t.Fatalf("predicates of %+v", searchErr)
+			}
+			if !strings.Contains(err.Error(), strconv.Itoa(test.status)) {
+				t.Fatalf("Error() = %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestSearchUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	client := NewSearchClient[searchTestProduct]("key", server.URL, false).WithoutAutoAlias()
+
+	_, err := client.Search(NewSearchParams().AddSearchTerm("shoe"))
+	var searchErr *SearchError
+	if !errors.As(err, &searchErr) || !searchErr.IsServerError() || searchErr.Err == nil || searchErr.StatusCode != 0 {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestSearchParamsError(t *testing.T) {
+	var requests int64
+	server := newSearchTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+	})
+	client := NewSearchClient[searchTestProduct]("key", server.URL, false).WithoutAutoAlias()
+
+	_, err := client.Search(NewSearchParams().AddSortByField(Field[searchTestProduct]("Missing"), SortAsc))
+	if err == nil || atomic.LoadInt64(&requests) != 0 {
+		t.Fatalf("err = %v , requests = %d , want the params error without searching", err, requests)
+	}
+}
+
+func TestHighlightsUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Highlights
+	}{
+		{name: "no tokens", json: `{"field":"name","snippet":"x"}`, want: Highlights{Field: "name", Snippet: "x"}},
+		{name: "null tokens", json: `{"field":"name","matched_tokens":null}`, want: Highlights{Field: "name"}},
+		{name: "empty tokens", json: `{"field":"name","matched_tokens":[]}`, want: Highlights{Field: "name", MatchedTokens: []string{}}},
+		{
+			name: "array field",
+			json: `{"field":"tags","matched_tokens":[ ["a","b"],["c"] ],"indices":[0,2]}`,
+			want: Highlights{
+				Field:              "tags",
+				MatchedTokens:      []string{"a", "b", "c"},
+				Indices:            []int{0, 2},
+				ArrayMatchedTokens: [][]string{{"a", "b"}, {"c"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got Highlights
+			err := json.Unmarshal([]byte(test.json), &got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %+v , want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestVectorQueryString(t *testing.T) {
+	tests := []struct {
+		name  string
+		query VectorQuery
+		want  string
+	}{
+		{name: "vector", query: VectorQuery{Field: "embedding", Vector: []float32{0.96826, 0.94, 0.39557}}, want: "embedding:([0.96826, 0.94, 0.39557])"},
+		{
+			name:  "vector with k and distance threshold",
+			query: VectorQuery{Field: "embedding", Vector: []float32{0.96826, 0.94, 0.39557}, K: 100, DistanceThreshold: 0.3},
+			want:  "embedding:([0.96826, 0.94, 0.39557], k:100, distance_threshold:0.3)",
+		},
+		{name: "similar document", query: VectorQuery{Field: "embedding", ID: "123", K: 5}, want: "embedding:([], id:123, k:5)"},
+		{name: "embedded query text", query: VectorQuery{Field: "embedding"}, want: "embedding:([])"},
+		{name: "alpha", query: VectorQuery{Field: "embedding", Alpha: 0.8}, want: "embedding:([], alpha:0.8)"},
+		{
+			name:  "every option",
+			query: VectorQuery{Field: "vec", Vector: []float32{-1, 2.5}, ID: "a", K: 10, DistanceThreshold: 0.25, Alpha: 0.5},
+			want:  "vec:([-1, 2.5], id:a, k:10, distance_threshold:0.25, alpha:0.5)",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.query.String(); got != test.want {
+				t.Fatalf("got %s , want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAddHybridQuery(t *testing.T) {
+	params := NewSearchParams().
+		AddHybridQuery("running shoes", "name,embedding", &VectorQuery{Field: "embedding", Alpha: 0.8}).
+		AddRerankHybridMatches(true)
+	query, err := toQueryParams(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"q":                     "running shoes",
+		"query_by":              "name,embedding",
+		"vector_query":          "embedding:([], alpha:0.8)",
+		"rerank_hybrid_matches": "true",
+	}
+	for key, value := range want {
+		if query[key] != value {
+			t.Fatalf("%s = %q , want %q (%v)", key, query[key], value, query)
+		}
+	}
+}
+
+func TestSearchVectorHits(t *testing.T) {
+	server := newSearchTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("vector_query"); got != "embedding:([], id:1, k:2)" {
+			t.Errorf("vector_query = %s", got)
+		}
+		_, _ = w.Write([]byte(`{
+  "found": 1,
+  "hits": [
+    {
+      "document": {"id": "2", "name": "Adidas shoe"},
+      "highlights": [],
+      "text_match": 0,
+      "vector_distance": 0.19744956493377686
+    }
+  ],
+  "out_of": 3,
+  "page": 1,
+  "search_time_ms": 0
+}`))
+	})
+	client := NewSearchClient[searchTestProduct]("key", server.URL, false)
+
+	res, err := client.Search(NewSearchParams().AddVectorQuery(&VectorQuery{Field: "embedding", ID: "1", K: 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 1 || res.Hits[0].Document.ID != "2" || res.Hits[0].VectorDistance < 0.1974 || res.Hits[0].VectorDistance > 0.1975 {
+		t.Fatalf("hits = %+v", res.Hits)
+	}
+}
@@ -0,0 +1,256 @@
+package typesense
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/baderkha/typesense/filter"
+	"github.com/baderkha/typesense/types"
+)
+
+const (
+	// SortMissingFirst : documents without a value for the field come first
+	SortMissingFirst = "first"
+	// SortMissingLast : documents without a value for the field come last (typesense default)
+	SortMissingLast = "last"
+
+	// maxSortClauses : typesense sorts by at most 3 fields
+	maxSortClauses = 3
+)
+
+// SortExpr : a sort_by expression (ie a SortBuilder)
+type SortExpr interface {
+	// Build : the sort_by string , errors if the sort is invalid
+	Build() (string, error)
+}
+
+// SortBuilder : builds a sort_by expression for the model T , fields are referenced by their go name
+// (dotted for nested structs) and checked against the tsense_sort / tsense_default_sort tags .
+// when T is not a struct (ie map[string]interface{}) field names are used as is
+//
+// Example :
+//			// _text_match(buckets: 10):desc,_eval(in_stock:true):desc,rating(missing_values: last):desc
+//			sortBy := typesense.
+//				NewSortBy[Product]().
+//				TextMatch(typesense.SortDesc, 10).
+//				Eval(typesense.SortDesc, filter.Field("in_stock").Eq(true)).
+//				Desc("Rating").MissingValues(typesense.SortMissingLast)
+//			params := typesense.NewSearchParams().AddSort(sortBy)
+//
+type SortBuilder[T any] struct {
+	clauses []sortClause
+	err     error
+}
+
+// sortClause : field(args):order
+type sortClause struct {
+	field   string
+	args    []string
+	order   string
+	special bool
+	geo     bool
+}
+
+func (c sortClause) String() string {
+	if len(c.args) == 0 {
+		return fmt.Sprintf("%s:%s", c.field, c.order)
+	}
+	return fmt.Sprintf("%s(%s):%s", c.field, strings.Join(c.args, ", "), c.order)
+}
+
+// EvalCondition : a weighted condition of an _eval sort , documents matching higher weights rank first
+type EvalCondition struct {
+	Filter filter.Expr
+	Weight int
+}
+
+// NewSortBy : a new empty sort_by builder for the model T
+func NewSortBy[T any]() *SortBuilder[T] {
+	return &SortBuilder[T]{}
+}
+
+// Asc : sort by the field ascending
+func (b *SortBuilder[T]) Asc(goPath string) *SortBuilder[T] {
+	return b.field(goPath, SortAsc)
+}
+
+// Desc : sort by the field descending
+func (b *SortBuilder[T]) Desc(goPath string) *SortBuilder[T] {
+	return b.field(goPath, SortDesc)
+}
+
+// MissingValues : where documents without a value go for the last added field (SortMissingFirst / SortMissingLast)
+func (b *SortBuilder[T]) MissingValues(missingValues string) *SortBuilder[T] {
+	if missingValues != SortMissingFirst && missingValues != SortMissingLast {
+		return b.fail(fmt.Errorf("Typesense : unknown missing values '%s' , use first or last", missingValues))
+	}
+	clause, ok := b.last()
+	if !ok || clause.special || clause.geo {
+		return b.fail(fmt.Errorf("Typesense : missing values only applies after Asc / Desc"))
+	}
+	clause.args = append(clause.args, "missing_values: "+missingValues)
+	return b
+}
+
+// TextMatch : sort by the text match score , scores are grouped in buckets (0 to not bucket) so the next sort
+// fields can break ties between close matches
+func (b *SortBuilder[T]) TextMatch(order string, buckets int) *SortBuilder[T] {
+	clause := sortClause{field: "_text_match", order: order, special: true}
+	if buckets > 0 {
+		clause.args = []string{fmt.Sprintf("buckets: %d", buckets)}
+	}
+	return b.add(clause)
+}
+
+// SeqID : sort by the order the documents were indexed in
+func (b *SortBuilder[T]) SeqID(order string) *SortBuilder[T] {
+	return b.add(sortClause{field: "_seq_id", order: order, special: true})
+}
+
+// Eval : documents matching the filter rank first (desc) or last (asc)
+func (b *SortBuilder[T]) Eval(order string, expr filter.Expr) *SortBuilder[T] {
+	filterBy, err := b.buildFilter(expr)
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.add(sortClause{field: "_eval", args: []string{filterBy}, order: order, special: true})
+}
+
+// EvalWeighted : documents are ranked by the weight of the condition they match
+//
+// Example :
+//			// _eval([ (brand:=`Nike`):3, (brand:=`Adidas`):2 ]):desc
+//			typesense.NewSortBy[Product]().EvalWeighted(typesense.SortDesc,
+//				typesense.EvalCondition{Filter: filter.Field("brand").Exact("Nike"), Weight: 3},
+//				typesense.EvalCondition{Filter: filter.Field("brand").Exact("Adidas"), Weight: 2},
+//			)
+func (b *SortBuilder[T]) EvalWeighted(order string, conditions ...EvalCondition) *SortBuilder[T] {
+	if len(conditions) == 0 {
+		return b.fail(fmt.Errorf("Typesense : weighted eval sort needs at least one condition"))
+	}
+	weighted := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		filterBy, err := b.buildFilter(condition.Filter)
+		if err != nil {
+			return b.fail(err)
+		}
+		weighted = append(weighted, fmt.Sprintf("(%s):%d", filterBy, condition.Weight))
+	}
+	return b.add(sortClause{
+		field:   "_eval",
+		args:    []string{fmt.Sprintf("[ %s ]", strings.Join(weighted, ", "))},
+		order:   order,
+		special: true,
+	})
+}
+
+// GeoDistance : sort by the distance between the geopoint field and a point
+func (b *SortBuilder[T]) GeoDistance(goPath string, from types.GeoPoint, order string) *SortBuilder[T] {
+	name, err := b.resolve(goPath, false)
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.add(sortClause{
+		field: name,
+		args:  []string{formatFloat(from.Lat), formatFloat(from.Lng)},
+		order: order,
+		geo:   true,
+	})
+}
+
+// ExcludeRadius : documents within the radius of the last added geo distance sort are treated as the same distance
+// (so the next sort fields decide their order) , unit is km or mi
+func (b *SortBuilder[T]) ExcludeRadius(radius float64, unit string) *SortBuilder[T] {
+	return b.geoOption("exclude_radius", radius, unit)
+}
+
+// Precision : groups the distances of the last added geo distance sort in buckets of this size , unit is km or mi
+func (b *SortBuilder[T]) Precision(precision float64, unit string) *SortBuilder[T] {
+	return b.geoOption("precision", precision, unit)
+}
+
+// Build : the sort_by string
+func (b *SortBuilder[T]) Build() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if len(b.clauses) > maxSortClauses {
+		return "", fmt.Errorf("Typesense : sort by supports at most %d fields , got %d", maxSortClauses, len(b.clauses))
+	}
+	clauses := make([]string, 0, len(b.clauses))
+	for _, clause := range b.clauses {
+		clauses = append(clauses, clause.String())
+	}
+	return strings.Join(clauses, ","), nil
+}
+
+func (b *SortBuilder[T]) field(goPath string, order string) *SortBuilder[T] {
+	name, err := b.resolve(goPath, true)
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.add(sortClause{field: name, order: order})
+}
+
+func (b *SortBuilder[T]) geoOption(option string, value float64, unit string) *SortBuilder[T] {
+	if unit != GeoUnitKm && unit != GeoUnitMi {
+		return b.fail(fmt.Errorf("Typesense : unknown geo unit '%s' , use km or mi", unit))
+	}
+	clause, ok := b.last()
+	if !ok || !clause.geo {
+		return b.fail(fmt.Errorf("Typesense : %s only applies after GeoDistance", option))
+	}
+	clause.args = append(clause.args, fmt.Sprintf("%s: %s%s", option, formatFloat(value), unit))
+	return b
+}
+
+// resolve : the typesense name of the go field , checked against the model tags when T is a struct
+func (b *SortBuilder[T]) resolve(goPath string, sortable bool) (string, error) {
+	modelType := derefType(reflect.TypeOf((*T)(nil)).Elem())
+	if modelType.Kind() != reflect.Struct {
+		return goPath, nil
+	}
+	ref := Field[T](goPath)
+	if sortable {
+		return ref.Sortable()
+	}
+	field, err := ref.Schema()
+	if err != nil {
+		return "", err
+	}
+	if field.Type != "geopoint" {
+		return "", fmt.Errorf("Typesense : field %s (%s) is not a geopoint , got %s", goPath, field.Name, field.Type)
+	}
+	return field.Name, nil
+}
+
+func (b *SortBuilder[T]) buildFilter(expr filter.Expr) (string, error) {
+	if expr == nil {
+		return "", fmt.Errorf("Typesense : eval sort needs a filter")
+	}
+	return expr.Build()
+}
+
+func (b *SortBuilder[T]) add(clause sortClause) *SortBuilder[T] {
+	if clause.order != SortAsc && clause.order != SortDesc {
+		return b.fail(fmt.Errorf("Typesense : unknown sort order '%s' for %s , use asc or desc", clause.order, clause.field))
+	}
+	b.clauses = append(b.clauses, clause)
+	return b
+}
+
+func (b *SortBuilder[T]) last() (*sortClause, bool) {
+	if len(b.clauses) == 0 {
+		return nil, false
+	}
+	return &b.clauses[len(b.clauses)-1], true
+}
+
+// fail : keeps the first error , returned by Build
+func (b *SortBuilder[T]) fail(err error) *SortBuilder[T] {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
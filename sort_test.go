@@ -0,0 +1,140 @@
+package typesense
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/baderkha/typesense/filter"
+	"github.com/baderkha/typesense/types"
+)
+
+type sortTestStore struct {
+	ID       string               `json:"id"`
+	Name     string               `json:"name"`
+	Brand    string               `json:"brand" tsense_facet:"1"`
+	Rating   float64              `json:"rating" tsense_sort:"1"`
+	Sales    int                  `json:"sales" tsense_default_sort:"1"`
+	Location types.GeoPoint       `json:"location"`
+	Address  sortTestStoreAddress `json:"address"`
+}
+
+type sortTestStoreAddress struct {
+	Zip string `json:"zip" tsense_sort:"1"`
+}
+
+func TestSortBuilder(t *testing.T) {
+	paris := types.GeoPoint{Lat: 48.853, Lng: 2.344}
+	tests := []struct {
+		name string
+		sort SortExpr
+		want string
+	}{
+		{name: "empty", sort: NewSortBy[sortTestStore](), want: ""},
+		{name: "fields", sort: NewSortBy[sortTestStore]().Desc("Rating").Asc("Sales"), want: "rating:desc,sales:asc"},
+		{name: "nested field", sort: NewSortBy[sortTestStore]().Asc("Address.Zip"), want: "address.zip:asc"},
+		{name: "text match", sort: NewSortBy[sortTestStore]().TextMatch(SortDesc, 0), want: "_text_match:desc"},
+		{
+			name: "text match buckets",
+			sort: NewSortBy[sortTestStore]().TextMatch(SortDesc, 10).Desc("Rating"),
+			want: "_text_match(buckets: 10):desc,rating:desc",
+		},
+		{name: "seq id", sort: NewSortBy[sortTestStore]().SeqID(SortAsc), want: "_seq_id:asc"},
+		{
+			name: "eval",
+			sort: NewSortBy[sortTestStore]().Eval(SortDesc, filter.Field("brand").Exact("Nike")),
+			want: "_eval(brand:=`Nike`):desc",
+		},
+		{
+			name: "eval with a group",
+			sort: NewSortBy[sortTestStore]().Eval(SortDesc, filter.Or(filter.Field("brand").Exact("Nike"), filter.Field("rating").Gt(4))),
+			want: "_eval(brand:=`Nike` || rating:>4):desc",
+		},
+		{
+			name: "weighted eval",
+			sort: NewSortBy[sortTestStore]().EvalWeighted(SortDesc,
+				EvalCondition{Filter: filter.Field("brand").Exact("Nike"), Weight: 3},
+				EvalCondition{Filter: filter.Field("brand").Exact("Adidas"), Weight: 2},
+			),
+			want: "_eval([ (brand:=`Nike`):3, (brand:=`Adidas`):2 ]):desc",
+		},
+		{name: "geo distance", sort: NewSortBy[sortTestStore]().GeoDistance("Location", paris, SortAsc), want: "location(48.853, 2.344):asc"},
+		{
+			name: "geo distance options",
+			sort: NewSortBy[sortTestStore]().GeoDistance("Location", paris, SortAsc).ExcludeRadius(2, GeoUnitMi).Precision(1, GeoUnitKm),
+			want: "location(48.853, 2.344, exclude_radius: 2mi, precision: 1km):asc",
+		},
+		{
+			name: "missing values",
+			sort: NewSortBy[sortTestStore]().Desc("Rating").MissingValues(SortMissingFirst).Asc("Sales").MissingValues(SortMissingLast),
+			want: "rating(missing_values: first):desc,sales(missing_values: last):asc",
+		},
+		{
+			name: "three clauses",
+			sort: NewSortBy[sortTestStore]().TextMatch(SortDesc, 10).Eval(SortDesc, filter.Field("brand").Exact("Nike")).Desc("Rating").MissingValues(SortMissingLast),
+			want: "_text_match(buckets: 10):desc,_eval(brand:=`Nike`):desc,rating(missing_values: last):desc",
+		},
+		{name: "map model uses names as is", sort: NewSortBy[map[string]interface{}]().Desc("popularity").GeoDistance("loc", paris, SortAsc), want: "popularity:desc,loc(48.853, 2.344):asc"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.sort.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("got %s , want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSortBuilderErrors(t *testing.T) {
+	paris := types.GeoPoint{Lat: 48.853, Lng: 2.344}
+	tests := []struct {
+		name    string
+		sort    SortExpr
+		wantErr string
+	}{
+		{
+			name:    "more than 3 clauses",
+			sort:    NewSortBy[sortTestStore]().TextMatch(SortDesc, 0).Desc("Rating").Asc("Sales").SeqID(SortAsc),
+			wantErr: "at most 3 fields , got 4",
+		},
+		{name: "not sortable", sort: NewSortBy[sortTestStore]().Asc("Brand"), wantErr: "brand) is not sortable"},
+		{name: "unknown field", sort: NewSortBy[sortTestStore]().Asc("Missing"), wantErr: "no exported field Missing"},
+		{name: "not a geopoint", sort: NewSortBy[sortTestStore]().GeoDistance("Name", paris, SortAsc), wantErr: "name) is not a geopoint , got string"},
+		{name: "unknown order", sort: NewSortBy[sortTestStore]().Desc("Rating").TextMatch("up", 0), wantErr: "unknown sort order 'up' for _text_match"},
+		{name: "missing values first", sort: NewSortBy[sortTestStore]().MissingValues(SortMissingLast), wantErr: "only applies after Asc / Desc"},
+		{name: "missing values after text match", sort: NewSortBy[sortTestStore]().TextMatch(SortDesc, 0).MissingValues(SortMissingLast), wantErr: "only applies after Asc / Desc"},
+		{name: "missing values after geo", sort: NewSortBy[sortTestStore]().GeoDistance("Location", paris, SortAsc).MissingValues(SortMissingLast), wantErr: "only applies after Asc / Desc"},
+		{name: "unknown missing values", sort: NewSortBy[sortTestStore]().Desc("Rating").MissingValues("middle"), wantErr: "unknown missing values 'middle'"},
+		{name: "exclude radius without geo", sort: NewSortBy[sortTestStore]().Desc("Rating").ExcludeRadius(2, GeoUnitMi), wantErr: "exclude_radius only applies after GeoDistance"},
+		{name: "unknown geo unit", sort: NewSortBy[sortTestStore]().GeoDistance("Location", paris, SortAsc).Precision(1, "m"), wantErr: "unknown geo unit 'm'"},
+		{name: "eval without a filter", sort: NewSortBy[sortTestStore]().Eval(SortDesc, nil), wantErr: "eval sort needs a filter"},
+		{name: "weighted eval without conditions", sort: NewSortBy[sortTestStore]().EvalWeighted(SortDesc), wantErr: "at least one condition"},
+		{
+			name:    "first error is kept",
+			sort:    NewSortBy[sortTestStore]().Asc("Brand").Asc("Missing"),
+			wantErr: "brand) is not sortable",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.sort.Build()
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("err = %v , want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddSort(t *testing.T) {
+	params := NewSearchParams().AddSort(NewSortBy[sortTestStore]().Desc("Rating"))
+	if params.SortBy != "rating:desc" || params.err != nil {
+		t.Fatalf("sort by = %q , err = %v", params.SortBy, params.err)
+	}
+	params = NewSearchParams().AddSort(NewSortBy[sortTestStore]().Asc("Brand"))
+	if params.err == nil || params.SortBy != "" {
+		t.Fatalf("sort by = %q , want the sort error", params.SortBy)
+	}
+}
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// GeoPoint : a latitude / longitude pair , typesense stores it as a [lat, lng] array (geopoint field)
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+// MarshalJSON encodes the point as a [lat, lng] array
+func (p GeoPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]float64{p.Lat, p.Lng})
+}
+
+// UnmarshalJSON decodes a [lat, lng] array into the point
+func (p *GeoPoint) UnmarshalJSON(bytes []byte) error {
+	var raw []float64
+	err := json.Unmarshal(bytes, &raw)
+	if err != nil {
+		return err
+	}
+	if len(raw) != 2 {
+		return fmt.Errorf("error decoding geopoint: expected [lat, lng] got %d values", len(raw))
+	}
+	p.Lat = raw[0]
+	p.Lng = raw[1]
+	return nil
+}
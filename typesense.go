@@ -1,6 +1,8 @@
 package typesense
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -54,6 +56,28 @@ type CollectionField struct {
 
 	Name string `json:"name"`
 	Type string `json:"type"`
+
+	// vector search (float[] fields)
+	NumDim  int         `json:"num_dim,omitempty"`
+	VecDist string      `json:"vec_dist,omitempty"`
+	Embed   *FieldEmbed `json:"embed,omitempty"`
+}
+
+// FieldEmbed : generates the embedding of a float[] field from other fields of the document
+type FieldEmbed struct {
+	From        []string         `json:"from"`
+	ModelConfig EmbedModelConfig `json:"model_config"`
+}
+
+// EmbedModelConfig : the model typesense uses to generate embeddings
+//
+// see https://typesense.org/docs/latest/api/vector-search.html#option-b-auto-embedding-generation-within-typesense
+type EmbedModelConfig struct {
+	ModelName      string `json:"model_name"`
+	APIKey         string `json:"api_key,omitempty"`
+	URL            string `json:"url,omitempty"`
+	IndexingPrefix string `json:"indexing_prefix,omitempty"`
+	QueryPrefix    string `json:"query_prefix,omitempty"`
 }
 
 // Collection : typesense collection
@@ -61,33 +85,53 @@ type Collection struct {
 	Name                string            `json:"name"`
 	Fields              []CollectionField `json:"fields"`
 	DefaultSortingField string            `json:"default_sorting_field"`
+	EnableNestedFields  bool              `json:"enable_nested_fields,omitempty"`
+	// read only , filled in by typesense
+	CreatedAt    int64 `json:"created_at,omitempty"`
+	NumDocuments int64 `json:"num_documents,omitempty"`
 }
 
-// CollectionField : field for a typesense collection
+// CollectionFieldUpdate : field change for a typesense collection schema update
 type CollectionFieldUpdate struct {
 	Facet    bool   `json:"facet"`
 	Index    bool   `json:"index"`
 	Optional bool   `json:"optional"`
 	Sort     bool   `json:"sort"`
-	Drop     bool   `json:"drop"`
+	Drop     bool   `json:"drop,omitempty"`
 	Name     string `json:"name"`
-	Type     string `json:"type"`
+	Type     string `json:"type,omitempty"`
+
+	NumDim  int         `json:"num_dim,omitempty"`
+	VecDist string      `json:"vec_dist,omitempty"`
+	Embed   *FieldEmbed `json:"embed,omitempty"`
 }
 
-// Collection : typesense collection
+// MarshalJSON : typesense only accepts the name alongside the drop flag
+func (c CollectionFieldUpdate) MarshalJSON() ([]byte, error) {
+	if c.Drop {
+		return json.Marshal(map[string]interface{}{
+			"name": c.Name,
+			"drop": true,
+		})
+	}
+	type fieldUpdate CollectionFieldUpdate
+	return json.Marshal(fieldUpdate(c))
+}
+
+// CollectionUpdate : typesense collection schema update
 type CollectionUpdate struct {
-	Fields              []CollectionField `json:"fields"`
-	DefaultSortingField string            `json:"default_sorting_field"`
+	Fields              []CollectionFieldUpdate `json:"fields"`
+	DefaultSortingField string                  `json:"default_sorting_field,omitempty"`
 }
 
 // Alias : alias to a collection
 type Alias struct {
-	Name           string `json:"name "`
+	Name           string `json:"name"`
 	CollectionName string `json:"collection_name"`
 }
 
 type SearchResultBase struct {
-	FacetCounts   []string      `json:"facet_counts"`
+	FacetCounts   []FacetCount  `json:"facet_counts"`
 	Found         int           `json:"found"`
 	OutOf         int           `json:"out_of"`
 	Page          int           `json:"page"`
@@ -95,6 +139,53 @@ type SearchResultBase struct {
 	SearchTimeMs  int           `json:"search_time_ms"`
 }
 
+// Facet : the facet counts of a field (facet_by) , false if the field wasn't faceted
+func (s *SearchResultBase) Facet(fieldName string) (FacetCount, bool) {
+	for _, facet := range s.FacetCounts {
+		if facet.FieldName == fieldName {
+			return facet, true
+		}
+	}
+	return FacetCount{}, false
+}
+
+// FacetValues : the values and their counts for a faceted field
+//
+// Example :
+//			for _, brand := range res.FacetValues("brand") {
+//				fmt.Println(brand.Value, brand.Count)
+//			}
+func (s *SearchResultBase) FacetValues(fieldName string) []FacetValueCount {
+	facet, _ := s.Facet(fieldName)
+	return facet.Counts
+}
+
+// FacetCount : facet values of a field
+type FacetCount struct {
+	FieldName string            `json:"field_name"`
+	Counts    []FacetValueCount `json:"counts"`
+	Stats     FacetStats        `json:"stats"`
+	Sampled   bool              `json:"sampled"`
+}
+
+// FacetValueCount : a facet value and how many documents have it
+type FacetValueCount struct {
+	Count       int    `json:"count"`
+	Highlighted string `json:"highlighted"`
+	Value       string `json:"value"`
+	// Parent : the parent object of the value in nested fields (facet_return_parent)
+	Parent map[string]interface{} `json:"parent,omitempty"`
+}
+
+// FacetStats : stats of numeric facet fields
+type FacetStats struct {
+	Avg         float64 `json:"avg"`
+	Max         float64 `json:"max"`
+	Min         float64 `json:"min"`
+	Sum         float64 `json:"sum"`
+	TotalValues int     `json:"total_values"`
+}
+
 // SearchResult : search result without grouping
 type SearchResult[T any] struct {
 	SearchResultBase
@@ -134,9 +225,36 @@ func (s *SearchResultGrouped[T]) GetDocuments() map[string][]*T {
 
 // Hits : results , houses your documents
 type Hit[T any] struct {
-	Document   T          `json:"document"`
-	Highlights Highlights `json:"highlights"`
-	TextMatch  int        `json:"text_match"`
+	Document T `json:"document"`
+	// Highlights : one entry per highlighted field (typesense sends a list , empty when nothing is highlighted)
+	Highlights []Highlights `json:"highlights"`
+	// Highlight : the highlighted fields keyed like the document (typesense >= 0.24)
+	Highlight map[string]interface{} `json:"highlight,omitempty"`
+	TextMatch int                    `json:"text_match"`
+	// GeoDistanceMeters : distance per geopoint field when sorting by geo distance
+	GeoDistanceMeters map[string]float64 `json:"geo_distance_meters,omitempty"`
+	// VectorDistance : distance to the query vector when searching with a vector query
+	VectorDistance float64 `json:"vector_distance,omitempty"`
+	// TextMatchInfo : breakdown of the text match score
+	TextMatchInfo *TextMatchInfo `json:"text_match_info,omitempty"`
+	// HybridSearchInfo : the fused score when doing a hybrid search
+	HybridSearchInfo *HybridSearchInfo `json:"hybrid_search_info,omitempty"`
+}
+
+// TextMatchInfo : breakdown of the text match score of a hit
+type TextMatchInfo struct {
+	BestFieldScore   string `json:"best_field_score"`
+	BestFieldWeight  int    `json:"best_field_weight"`
+	FieldsMatched    int    `json:"fields_matched"`
+	NumTokensDropped int    `json:"num_tokens_dropped"`
+	Score            string `json:"score"`
+	TokensMatched    int    `json:"tokens_matched"`
+	TypoPrefixScore  int    `json:"typo_prefix_score"`
+}
+
+// HybridSearchInfo : rank fusion score of a hybrid search hit
+type HybridSearchInfo struct {
+	RankFusionScore float64 `json:"rank_fusion_score"`
 }
 
 // GroupedHits : results , houses your documents and group by info
@@ -157,6 +275,54 @@ type Highlights struct {
 	Field         string   `json:"field"`
 	MatchedTokens []string `json:"matched_tokens"`
 	Snippet       string   `json:"snippet"`
+	Value         string   `json:"value,omitempty"`
+
+	// array fields : one entry per highlighted element
+	Snippets []string `json:"snippets,omitempty"`
+	Indices  []int    `json:"indices,omitempty"`
+	// ArrayMatchedTokens : the matched tokens of each highlighted element of an array field (MatchedTokens has them all)
+	ArrayMatchedTokens [][]string `json:"-"`
+}
+
+// UnmarshalJSON : matched_tokens is a list of lists for array fields
+func (h *Highlights) UnmarshalJSON(data []byte) error {
+	type highlights Highlights
+	var raw struct {
+		highlights
+		MatchedTokens json.RawMessage `json:"matched_tokens"`
+	}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	*h = Highlights(raw.highlights)
+	tokens := bytes.TrimSpace(raw.MatchedTokens)
+	if len(tokens) == 0 || bytes.Equal(tokens, []byte("null")) {
+		return nil
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(tokens[1:]), []byte("[")) {
+		err = json.Unmarshal(tokens, &h.ArrayMatchedTokens)
+		for _, elementTokens := range h.ArrayMatchedTokens {
+			h.MatchedTokens = append(h.MatchedTokens, elementTokens...)
+		}
+		return err
+	}
+	return json.Unmarshal(tokens, &h.MatchedTokens)
+}
+
+// MarshalJSON : writes the matched tokens of array fields back as a list of lists
+func (h Highlights) MarshalJSON() ([]byte, error) {
+	type highlights Highlights
+	if h.ArrayMatchedTokens == nil {
+		return json.Marshal(highlights(h))
+	}
+	return json.Marshal(struct {
+		highlights
+		MatchedTokens [][]string `json:"matched_tokens"`
+	}{
+		highlights:    highlights(h),
+		MatchedTokens: h.ArrayMatchedTokens,
+	})
 }
 
 func newHTTPClient(apiKey, host string, logging bool) *resty.Client {